@@ -1,25 +1,59 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/ancientlore/vbscribble/vblexer"
 	"github.com/ancientlore/vbscribble/vbscanner"
+	"github.com/ancientlore/vbscribble/vbwsf"
 )
 
 var (
 	respWrite = flag.Bool("rw", false, "Use Response.Write formatting")
+	write     = flag.Bool("w", false, "Write the result to the source file instead of stdout")
+	encoding  = flag.String("encoding", "", "Encoding of the source files and of stdout, like utf-8 or windows-1252; if not set it is detected and stdout is UTF-8")
+	eol       = flag.String("eol", "preserve", "Line endings to write: preserve, crlf, lf or cr")
 )
 
+// lineEnds maps the values of the -eol flag to line endings.
+var lineEnds = map[string]string{
+	"preserve": "",
+	"crlf":     "\r\n",
+	"lf":       "\n",
+	"cr":       "\r",
+}
+
+// lineEnd matches a line ending.
+var lineEnd = regexp.MustCompile(`\r\n?|\n`)
+
+// out receives the formatted code.
+var out io.Writer = os.Stdout
+
 func main() {
 	flag.Parse()
 
+	enc := vbscanner.DetectEncoding
+	if *encoding != "" {
+		var err error
+		enc, err = vbscanner.ParseEncoding(*encoding)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	nl, ok := lineEnds[strings.ToLower(*eol)]
+	if !ok {
+		log.Fatalf("unknown line ending %q", *eol)
+	}
+
 	for _, pattern := range flag.Args() {
 		files, err := filepath.Glob(pattern)
 		if err != nil {
@@ -36,232 +70,355 @@ func main() {
 				if err != nil {
 					log.Fatal(err)
 				}
-				func(fil io.Reader, f string) {
+				var buf bytes.Buffer
+				out = &buf
+				var ok bool
+				var srcEnc vbscanner.Encoding
+				if strings.EqualFold(filepath.Ext(f), ".wsf") {
+					ok, srcEnc = formatWSF(fil, f, enc)
+				} else {
 					var lex vblexer.Lex
-					defer func() {
-						if r := recover(); r != nil {
-							log.Print("PARSE ERROR ", f, ":", lex.Line, ": ", r)
-						}
-					}()
-					lex.Init(fil, f, vbscanner.HTML_MODE)
-					aft := ""
-					tabs := 0
-					startLine := true
-					paren := false
-					prevK := vblexer.EOF
-					var prevT interface{}
-					needStarter := false
-					remTabAfterEOL := false
-					noTabs := false
-					if *respWrite {
-						fmt.Print("<%")
+					lex.InitFile(fil, f)
+					lex.SetEncoding(enc)
+					ext := strings.ToLower(filepath.Ext(f))
+					ok = format(&lex, f, *respWrite && ext != ".vbs" && ext != ".hta")
+					srcEnc = lex.Encoding()
+				}
+				fil.Close()
+				text := buf.Bytes()
+				if nl != "" {
+					text = lineEnd.ReplaceAllLiteral(text, []byte(nl))
+				}
+				if *write {
+					if !ok {
+						log.Print("not writing ", f)
+						continue
 					}
-					for k, t, v := lex.Lex(); k != vblexer.EOF; k, t, v = lex.Lex() {
-						if needStarter {
-							if k != vblexer.FILE_INCLUDE && k != vblexer.VIRTUAL_INCLUDE && k != vblexer.HTML {
-								fmt.Print("<%")
-							}
-							needStarter = false
-						}
-						if startLine {
-							if k == vblexer.STATEMENT {
-								if t == "End" {
-									pv := v
-									k, t, v = lex.Lex()
-									if k != vblexer.EOF {
-										t = "End " + t.(string)
-										v = pv + " " + v
-										tabs--
-										/*
-											if t == "End Select" {
-												tabs--
-											}
-										*/
-									}
-								}
-								switch t {
-								case "Else", "Elseif", "Case", "Wend", "Next", "Loop":
-									tabs--
-								}
-							}
-							if tabs < 0 {
-								tabs = 0
-							}
-							if prevK != vblexer.HTML && !noTabs {
-								fmt.Print(strings.Repeat("\t", tabs))
-							}
-							noTabs = false
-							if remTabAfterEOL {
-								remTabAfterEOL = false
-								tabs--
-							}
-							startLine = false
-							aft = ""
-							paren = false
-						} else {
-							aft = " "
-						}
-						if paren {
-							paren = false
-							aft = ""
-						}
-						if prevK == vblexer.STATEMENT && prevT == "Then" {
-							if k != vblexer.EOL && k != vblexer.HTML {
-								tabs--
-							}
-						}
-						switch k {
-						case vblexer.EOF:
-						case vblexer.STATEMENT:
-							fmt.Print(aft)
-							switch t {
-							case "Elseif":
-								fmt.Print("ElseIf")
-							case "Redim":
-								fmt.Print("ReDim")
-							case "Executeglobal":
-								fmt.Print("ExecuteGlobal")
-							case "Wend":
-								fmt.Print("WEnd")
-							case "Byref":
-								fmt.Print("ByRef")
-							case "Byval":
-								fmt.Print("ByVal")
-							default:
-								fmt.Print(t)
-							}
-							switch t {
-							case "If", "Function", "Sub", "Class", "Property", "For", "With", "While", "Case": // "Select"
-								if !(prevK == vblexer.STATEMENT && prevT == "Exit") {
-									tabs++
-								}
-							case "Else":
-								if !(prevK == vblexer.STATEMENT && prevT == "Case") {
-									tabs++
-								}
-							case "Elseif": // "Do"
-								tabs++
-							}
-						case vblexer.FUNCTION:
-							fmt.Print(aft)
-							fmt.Print(t)
-						case vblexer.KEYWORD, vblexer.KEYWORD_BOOL:
-							fmt.Print(aft)
-							fmt.Print(t)
-						case vblexer.COLOR_CONSTANT, vblexer.COMPARE_CONSTANT, vblexer.DATE_CONSTANT, vblexer.DATEFORMAT_CONSTANT, vblexer.MISC_CONSTANT, vblexer.MSGBOX_CONSTANT, vblexer.STRING_CONSTANT, vblexer.TRISTATE_CONSTANT, vblexer.VARTYPE_CONSTANT:
-							fmt.Print(aft)
-							fmt.Print(t)
-						case vblexer.IDENTIFIER:
-							fmt.Print(aft)
-							fmt.Print(t)
-						case vblexer.STRING:
-							fmt.Print(aft)
-							fmt.Printf("\"%s\"", strings.Replace(v, "\"", "\"\"", -1))
-						case vblexer.INT:
-							fmt.Print(aft)
-							fmt.Print(v)
-						case vblexer.FLOAT:
-							fmt.Print(aft)
-							fmt.Print(v)
-						case vblexer.DATE:
-							fmt.Print(aft)
-							fmt.Print("#", v, "#")
-						case vblexer.COMMENT:
-							fmt.Print(aft)
-							fmt.Printf("' %s", t)
-						case vblexer.HTML:
-							if *respWrite {
-								lines := strings.Split(strings.Replace(v, "\r", "", -1), "\n")
-								for index, line := range lines {
-									if index == 0 {
-										fmt.Println()
-										fmt.Print(aft)
-										fmt.Print("Response.Write ")
-									} else {
-										fmt.Print(strings.Repeat("\t", tabs+1))
-										fmt.Print("& vbCrLf & ")
-									}
-									fmt.Printf("\"%s\"\n", strings.Replace(line, "\"", "\"\"", -1))
-								}
-							} else {
-								if prevK != vblexer.EOF && prevK != vblexer.FILE_INCLUDE && prevK != vblexer.VIRTUAL_INCLUDE && prevK != vblexer.HTML {
-									fmt.Print(aft)
-									fmt.Print("%>")
-								}
-								fmt.Print(v)
-								needStarter = true
-							}
-							startLine = true
-						case vblexer.FILE_INCLUDE:
-							if prevK != vblexer.HTML && prevK != vblexer.FILE_INCLUDE && prevK != vblexer.VIRTUAL_INCLUDE {
-								fmt.Print(aft)
-								fmt.Print("%>")
-							}
-							if *respWrite {
-								fmt.Print(aft)
-								fmt.Print("%>")
-							}
-							fmt.Printf(`<!--#include file="%s"-->`, v)
-							if *respWrite {
-								fmt.Print("<%")
-								fmt.Print(aft)
-							}
-							needStarter = true
-							startLine = true
-						case vblexer.VIRTUAL_INCLUDE:
-							if prevK != vblexer.HTML && prevK != vblexer.FILE_INCLUDE && prevK != vblexer.VIRTUAL_INCLUDE {
-								fmt.Print(aft)
-								fmt.Print("%>")
-							}
-							if *respWrite {
-								fmt.Print(aft)
-								fmt.Print("%>")
-							}
-							fmt.Printf(`<!--#include virtual="%s"-->`, v)
-							if *respWrite {
-								fmt.Print("<%")
-								fmt.Print(aft)
-							}
-							needStarter = true
-							startLine = true
-						case vblexer.CHAR:
-							if prevK == vblexer.STATEMENT || prevK == vblexer.OP {
-								fmt.Print(aft)
-							}
-							fmt.Print(t)
-							if t == "(" {
-								paren = true
-							}
-						case vblexer.EOL:
-							if t == ":" {
-								fmt.Print(aft)
-								fmt.Print(t)
-								fmt.Print(" ")
-								noTabs = true
-							} else {
-								fmt.Println()
-							}
-							startLine = true
-						case vblexer.OP:
-							fmt.Print(aft)
-							fmt.Print(t)
-						case vblexer.CONTINUATION:
-							fmt.Print(aft)
-							fmt.Print(t)
-							tabs++
-							remTabAfterEOL = true
-						default:
-							panic("Unexpected token type")
+					if err := writeFile(f, text, srcEnc, fi.Mode()); err != nil {
+						log.Fatal(err)
+					}
+				} else {
+					// stdout is UTF-8 unless an encoding was asked for
+					w := io.Writer(os.Stdout)
+					if *encoding != "" {
+						w = vbscanner.NewEncoder(os.Stdout, srcEnc)
+					}
+					if _, err := w.Write(text); err != nil {
+						log.Fatal(err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// writeFile replaces the contents of the file f with text, converted to enc.
+func writeFile(f string, text []byte, enc vbscanner.Encoding, perm os.FileMode) error {
+	var buf bytes.Buffer
+	if _, err := vbscanner.NewEncoder(&buf, enc).Write(text); err != nil {
+		return err
+	}
+	return os.WriteFile(f, buf.Bytes(), perm)
+}
+
+// format prints the formatted code read by lex, returning false if it could
+// not be parsed or the lexer found problems, which are logged. If rw is set,
+// HTML is written with Response.Write, which only applies to ASP pages.
+func format(lex *vblexer.Lex, f string, rw bool) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Print("PARSE ERROR ", f, ":", lex.Line, ": ", r)
+			ok = false
+		}
+	}()
+	aft := ""
+	tabs := 0
+	startLine := true
+	paren := false
+	prevK := vblexer.EOF
+	var prevT interface{}
+	needStarter := false
+	remTabAfterEOL := false
+	noTabs := false
+	lex.SetCompound(true)
+	nl := "\n"  // line ending of the source, for the lines added by rw
+	open := !rw // with rw, whether the code block holding the page is open
+	for tok := range lex.All() {
+		k, t, v := tok.Type, tok.Value, tok.Raw
+		if !open && k != vblexer.DIRECTIVE && (k != vblexer.HTML || v != "") {
+			fmt.Fprint(out, "<%", nl)
+			open = true
+			startLine = true
+		}
+		lineStart := startLine
+		if needStarter {
+			if k != vblexer.FILE_INCLUDE && k != vblexer.VIRTUAL_INCLUDE && k != vblexer.HTML && k != vblexer.SCRIPT_TAG {
+				fmt.Fprint(out, "<%")
+			}
+			needStarter = false
+		}
+		if startLine {
+			switch k {
+			case vblexer.STATEMENT:
+				switch t {
+				case "Else", "ElseIf", "Case", "WEnd", "Next", "Loop":
+					tabs--
+				}
+			case vblexer.COMPOUND:
+				switch t {
+				case vblexer.EndIf, vblexer.EndFunction, vblexer.EndSub, vblexer.EndClass, vblexer.EndProperty, vblexer.EndSelect, vblexer.EndWith, vblexer.CaseElse:
+					tabs--
+				}
+			}
+			if tabs < 0 {
+				tabs = 0
+			}
+			if prevK != vblexer.HTML && !noTabs {
+				fmt.Fprint(out, strings.Repeat("\t", tabs))
+			}
+			noTabs = false
+			if remTabAfterEOL {
+				remTabAfterEOL = false
+				tabs--
+			}
+			startLine = false
+			aft = ""
+			paren = false
+		} else {
+			aft = " "
+		}
+		if paren {
+			paren = false
+			aft = ""
+		}
+		if prevK == vblexer.STATEMENT && prevT == "Then" {
+			if k != vblexer.EOL && k != vblexer.HTML {
+				tabs--
+			}
+		}
+		switch k {
+		case vblexer.EOF:
+		case vblexer.STATEMENT:
+			fmt.Fprint(out, aft)
+			if sym, ok := vblexer.Lookup(t.(string)); ok {
+				fmt.Fprint(out, sym.Name)
+			} else {
+				fmt.Fprint(out, t)
+			}
+			switch t {
+			case "If", "Function", "Sub", "Class", "Property", "For", "With", "While", "Case": // "Select"
+				tabs++
+			case "Else":
+				if !(prevK == vblexer.STATEMENT && prevT == "Case") {
+					tabs++
+				}
+			case "ElseIf": // "Do"
+				tabs++
+			}
+		case vblexer.COMPOUND:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, t.(vblexer.Compound).Statement())
+			switch t {
+			case vblexer.SelectCase, vblexer.CaseElse, vblexer.PropertyGet, vblexer.PropertyLet, vblexer.PropertySet, vblexer.ForEach:
+				tabs++
+			}
+		case vblexer.FUNCTION:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, t)
+		case vblexer.KEYWORD, vblexer.KEYWORD_BOOL:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, t)
+		case vblexer.COLOR_CONSTANT, vblexer.COMPARE_CONSTANT, vblexer.DATE_CONSTANT, vblexer.DATEFORMAT_CONSTANT, vblexer.MISC_CONSTANT, vblexer.MSGBOX_CONSTANT, vblexer.STRING_CONSTANT, vblexer.TRISTATE_CONSTANT, vblexer.VARTYPE_CONSTANT, vblexer.ADO_CONSTANT, vblexer.FSO_CONSTANT:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, t)
+		case vblexer.INTRINSIC_OBJECT, vblexer.INTRINSIC_MEMBER:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, t)
+		case vblexer.IDENTIFIER:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, t)
+		case vblexer.STRING:
+			fmt.Fprint(out, aft)
+			fmt.Fprintf(out, "\"%s\"", strings.Replace(v, "\"", "\"\"", -1))
+		case vblexer.INT:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, v)
+		case vblexer.FLOAT:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, v)
+		case vblexer.DATE:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, "#", v, "#")
+		case vblexer.ERROR:
+			fmt.Fprint(out, aft)
+			if err, ok := t.(error); ok && errors.Is(err, vblexer.ErrInvalidDate) {
+				fmt.Fprint(out, "#", v, "#")
+			} else {
+				fmt.Fprint(out, v)
+			}
+		case vblexer.COMMENT:
+			fmt.Fprint(out, aft)
+			fmt.Fprintf(out, "' %s", t)
+		case vblexer.HTML:
+			if rw {
+				if v == "" {
+					break
+				}
+				lines := lineEnd.Split(v, -1)
+				for index, line := range lines {
+					if index == 0 {
+						if !lineStart {
+							fmt.Fprint(out, nl)
+							fmt.Fprint(out, strings.Repeat("\t", tabs))
 						}
-						prevK = k
-						prevT = t
+						fmt.Fprint(out, "Response.Write ")
+					} else {
+						fmt.Fprint(out, strings.Repeat("\t", tabs+1))
+						fmt.Fprint(out, "& vbCrLf & ")
 					}
-					if *respWrite {
-						fmt.Println("%>")
+					fmt.Fprintf(out, "\"%s\"", strings.Replace(line, "\"", "\"\"", -1))
+					if index < len(lines)-1 {
+						fmt.Fprint(out, " _")
 					}
-				}(fil, f)
-				fil.Close()
+					fmt.Fprint(out, nl)
+				}
+			} else {
+				if prevK != vblexer.EOF && prevK != vblexer.FILE_INCLUDE && prevK != vblexer.VIRTUAL_INCLUDE && prevK != vblexer.HTML && prevK != vblexer.SCRIPT_TAG {
+					fmt.Fprint(out, aft)
+					fmt.Fprint(out, "%>")
+				}
+				fmt.Fprint(out, v)
+				needStarter = true
+			}
+			startLine = true
+		case vblexer.FILE_INCLUDE:
+			if prevK != vblexer.HTML && prevK != vblexer.FILE_INCLUDE && prevK != vblexer.VIRTUAL_INCLUDE {
+				fmt.Fprint(out, aft)
+				fmt.Fprint(out, "%>")
+			}
+			if rw {
+				fmt.Fprint(out, aft)
+				fmt.Fprint(out, "%>")
+			}
+			fmt.Fprintf(out, `<!--#include file="%s"-->`, v)
+			if rw {
+				fmt.Fprint(out, "<%")
+				fmt.Fprint(out, aft)
+			}
+			needStarter = true
+			startLine = true
+		case vblexer.VIRTUAL_INCLUDE:
+			if prevK != vblexer.HTML && prevK != vblexer.FILE_INCLUDE && prevK != vblexer.VIRTUAL_INCLUDE {
+				fmt.Fprint(out, aft)
+				fmt.Fprint(out, "%>")
+			}
+			if rw {
+				fmt.Fprint(out, aft)
+				fmt.Fprint(out, "%>")
+			}
+			fmt.Fprintf(out, `<!--#include virtual="%s"-->`, v)
+			if rw {
+				fmt.Fprint(out, "<%")
+				fmt.Fprint(out, aft)
+			}
+			needStarter = true
+			startLine = true
+		case vblexer.SCRIPT_TAG:
+			// server script blocks become part of the code when writing HTML with Response.Write
+			if !rw {
+				fmt.Fprint(out, v)
+			}
+			startLine = true
+		case vblexer.CHAR, vblexer.PAREN_OPEN, vblexer.PAREN_CLOSE, vblexer.LIST_SEP, vblexer.FIELD_SEP:
+			if prevK == vblexer.STATEMENT || prevK == vblexer.COMPOUND || prevK == vblexer.OP {
+				fmt.Fprint(out, aft)
+			}
+			fmt.Fprint(out, t)
+			if t == "(" || t == "." {
+				paren = true
+			}
+		case vblexer.EOL:
+			if t == ":" {
+				fmt.Fprint(out, aft)
+				fmt.Fprint(out, t)
+				fmt.Fprint(out, " ")
+				noTabs = true
+			} else {
+				nl = v
+				fmt.Fprint(out, nl)
+			}
+			startLine = true
+		case vblexer.OP:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, t)
+		case vblexer.OUTPUT:
+			// with rw, the output block becomes a statement in the code block
+			if rw {
+				fmt.Fprint(out, "Response.Write")
+			} else {
+				fmt.Fprint(out, t)
+			}
+		case vblexer.DIRECTIVE:
+			// with rw, the directive keeps its own block, which comes first
+			if rw {
+				fmt.Fprint(out, "<%@ ", strings.TrimSpace(v), " %>", nl)
+			} else {
+				fmt.Fprint(out, "@ ", strings.TrimSpace(v))
+			}
+		case vblexer.CONTINUATION:
+			fmt.Fprint(out, aft)
+			fmt.Fprint(out, t)
+			tabs++
+			remTabAfterEOL = true
+		default:
+			panic("Unexpected token type")
+		}
+		prevK = k
+		prevT = t
+	}
+	if rw {
+		if !open {
+			fmt.Fprint(out, "<%")
+		}
+		fmt.Fprint(out, "%>", nl)
+	}
+	for _, err := range lex.Errors() {
+		log.Print("PARSE ERROR ", err)
+	}
+	return len(lex.Errors()) == 0
+}
+
+// formatWSF prints a Windows Script File with its VBScript code formatted,
+// returning false if it could not be parsed, and the encoding of the file.
+// The rest of the file is printed as it is.
+func formatWSF(fil io.Reader, f string, enc vbscanner.Encoding) (bool, vbscanner.Encoding) {
+	dec, enc := vbscanner.NewDecoder(fil, enc)
+	src, err := io.ReadAll(dec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	wsf, err := vbwsf.Parse(string(src))
+	if err != nil {
+		log.Print("PARSE ERROR ", f, ":", err)
+		fmt.Fprint(out, string(src))
+		return false, enc
+	}
+	ok := true
+	prev := 0
+	for _, job := range wsf.Jobs {
+		for _, sc := range job.Scripts {
+			if !sc.IsVBScript() || sc.Src != "" {
+				continue
+			}
+			fmt.Fprint(out, string(src[prev:sc.Start.Offset]))
+			var lex vblexer.Lex
+			sc.Init(&lex, f)
+			if !format(&lex, f, false) {
+				ok = false
 			}
+			prev = sc.End.Offset
 		}
 	}
+	fmt.Fprint(out, string(src[prev:]))
+	return ok, enc
 }
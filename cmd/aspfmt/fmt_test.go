@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// formatString returns src formatted as the file f.
+func formatString(t *testing.T, src, f string, rw bool) string {
+	t.Helper()
+	var buf bytes.Buffer
+	out = &buf
+	defer func() { out = os.Stdout }()
+	var lex vblexer.Lex
+	lex.InitFile(strings.NewReader(src), f)
+	if !format(&lex, f, rw) {
+		t.Errorf("%q: not formatted", src)
+	}
+	return buf.String()
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"spacing", "x=1+2*y", "x = 1 + 2 * y"},
+		{"case", "DIM x\nx = len(\"a\")", "Dim x\nx = Len(\"a\")"},
+		{"if", "If a Then\nb\nElseIf c Then\nd\nElse\ne\nEnd If", "If a Then\n\tb\nElseIf c Then\n\td\nElse\n\te\nEnd If"},
+		{"single-line if", "If a Then b\nc", "If a Then b\nc"},
+		{"compound", "end  if\nend sub", "End If\nEnd Sub"},
+		{"sub", "Sub Foo(a)\nFor i = 1 To a\nWhile x\nWEnd\nNext\nEnd Sub", "Sub Foo(a)\n\tFor i = 1 To a\n\t\tWhile x\n\t\tWEnd\n\tNext\nEnd Sub"},
+		{"select", "Select Case x\nCase 1\na\nCase Else\nb\nEnd Select", "Select Case x\nCase 1\n\ta\nCase Else\n\tb\nEnd Select"},
+		{"class", "Class C\nPublic Property Get P\nP = 1\nEnd Property\nEnd Class", "Class C\n\tPublic Property Get P\n\t\tP = 1\n\tEnd Property\nEnd Class"},
+		{"for each", "For Each v In list\nfoo v\nNext", "For Each v In list\n\tfoo v\nNext"},
+		{"members", "Set rs = Server.CreateObject(\"ADODB.Recordset\")", "Set rs = Server.CreateObject(\"ADODB.Recordset\")"},
+		{"literals", "x = \"a\"\"b\" & #1/2/2003# & &HFF", "x = \"a\"\"b\" & #1/2/2003# & &HFF"},
+		{"comment", "x = 1 'note", "x = 1 ' note"},
+		{"continuation", "x = 1 + _\n2\ny = 3", "x = 1 + _\n\t2\ny = 3"},
+		{"crlf", "If a Then\r\nb\r\nEnd If\r\n", "If a Then\r\n\tb\r\nEnd If\r\n"},
+	}
+	for _, tt := range tests {
+		if got := formatString(t, tt.in, "test.vbs", false); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPage(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"html", "<p>hi</p>", "<p>hi</p>"},
+		{"code", "<p><% if a then %>yes<% end if %></p>", "<p><%If a Then %>yes<%End If %></p>"},
+		{"output", "<p><%=  x+1 %></p>", "<p><%= x + 1 %></p>"},
+		{"directive", "<%@ Language=VBScript %>\n<p>", "<%@ Language=VBScript %>\n<p>"},
+		{"include", "<!-- #include file=\"a.asp\" -->\n<% x %>", "<!--#include file=\"a.asp\"-->\n<%x %>"},
+	}
+	for _, tt := range tests {
+		if got := formatString(t, tt.in, "test.asp", false); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResponseWrite(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "rw.asp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := formatString(t, string(src), "rw.asp", true)
+	golden := filepath.Join("testdata", "rw.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLineEnds(t *testing.T) {
+	text := []byte("a\r\nb\nc\rd")
+	tests := map[string]string{
+		"crlf": "a\r\nb\r\nc\r\nd",
+		"lf":   "a\nb\nc\nd",
+		"cr":   "a\rb\rc\rd",
+	}
+	for flag, want := range tests {
+		nl, ok := lineEnds[flag]
+		if !ok {
+			t.Errorf("-eol %s: unknown", flag)
+			continue
+		}
+		if got := string(lineEnd.ReplaceAllLiteral(text, []byte(nl))); got != want {
+			t.Errorf("-eol %s: got %q, want %q", flag, got, want)
+		}
+	}
+	if nl := lineEnds["preserve"]; nl != "" {
+		t.Errorf("-eol preserve: got %q", nl)
+	}
+}
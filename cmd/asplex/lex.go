@@ -1,18 +1,30 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/ancientlore/vbscribble/vbinclude"
 	"github.com/ancientlore/vbscribble/vblexer"
 	"github.com/ancientlore/vbscribble/vbscanner"
+	"github.com/ancientlore/vbscribble/vbwsf"
 )
 
 func main() {
+	var (
+		includes = flag.Bool("includes", false, "follow #include directives")
+		root     = flag.String("root", ".", "web root for virtual includes")
+	)
+	vdirs := make(map[string]string)
+	flag.Func("vdir", "virtual directory mapping, like /shared=C:\\shared (may be repeated)", func(s string) error {
+		return addVdir(vdirs, s)
+	})
 	flag.Parse()
 
 	for _, pattern := range flag.Args() {
@@ -27,24 +39,105 @@ func main() {
 			}
 			if !fi.IsDir() {
 				fmt.Println("\n*** ", f, " ***")
+				if *includes {
+					lexIncludes(&vbinclude.Resolver{Root: *root, Virtual: vdirs}, f)
+					continue
+				}
 				fil, err := os.Open(f)
 				if err != nil {
 					log.Fatal(err)
 				}
-				func(fil io.Reader, f string) {
+				if strings.EqualFold(filepath.Ext(f), ".wsf") {
+					lexWSF(fil, f)
+				} else {
 					var lex vblexer.Lex
-					defer func() {
-						if r := recover(); r != nil {
-							log.Print("PARSE ERROR ", f, ":", lex.Line, ": ", r)
-						}
-					}()
-					lex.Init(fil, f, vbscanner.HTML_MODE)
-					for k, t, v := lex.Lex(); k != vblexer.EOF; k, t, v = lex.Lex() {
-						fmt.Printf("%8d %-10s %v %#v\n", lex.Line, k, t, v)
-					}
-				}(fil, f)
+					lex.InitFile(fil, f)
+					lexTokens(&lex, f)
+				}
 				fil.Close()
 			}
 		}
 	}
 }
+
+// addVdir adds the mapping of a virtual directory to a path given as
+// /virtual=path to vdirs.
+func addVdir(vdirs map[string]string, s string) error {
+	v, dir, ok := strings.Cut(s, "=")
+	if !ok || v == "" || dir == "" {
+		return errors.New("expected /virtual=path")
+	}
+	vdirs[v] = dir
+	return nil
+}
+
+// lexTokens prints the tokens read by lex.
+func lexTokens(lex *vblexer.Lex, f string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Print("PARSE ERROR ", f, ":", lex.Line, ": ", r)
+		}
+	}()
+	for tok := range lex.All() {
+		fmt.Printf("%8d %-10s %-10s %v %#v\n", lex.Line, tok.Start, tok.Type, tok.Value, tok.Raw)
+	}
+	for _, err := range lex.Errors() {
+		log.Print("PARSE ERROR ", err)
+	}
+}
+
+// lexWSF prints the jobs in a Windows Script File and the tokens of their
+// VBScript code.
+func lexWSF(fil io.Reader, f string) {
+	wsf, err := vbwsf.Read(fil)
+	if err != nil {
+		log.Print("PARSE ERROR ", f, ":", err)
+		return
+	}
+	for _, job := range wsf.Jobs {
+		fmt.Printf("%8d %-10s %-10s %q\n", job.Pos.Line, job.Pos, "JOB", job.ID)
+		for _, ref := range job.References {
+			fmt.Printf("%8d %-10s %-10s %s%s %s\n", ref.Pos.Line, ref.Pos, "REFERENCE", ref.Object, ref.GUID, ref.Version)
+		}
+		for _, obj := range job.Objects {
+			fmt.Printf("%8d %-10s %-10s %s %s%s\n", obj.Pos.Line, obj.Pos, "OBJECT", obj.ID, obj.ProgID, obj.ClassID)
+		}
+		for _, sc := range job.Scripts {
+			fmt.Printf("%8d %-10s %-10s %s %s\n", sc.Pos.Line, sc.Pos, "SCRIPT", sc.Language, sc.Src)
+			if sc.IsVBScript() {
+				var lex vblexer.Lex
+				sc.Init(&lex, f)
+				lexTokens(&lex, f)
+			}
+		}
+	}
+}
+
+// lexIncludes prints the tokens of f and the files it includes.
+func lexIncludes(r *vbinclude.Resolver, f string) {
+	var lex vbinclude.Lex
+	defer lex.Close()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Print("PARSE ERROR ", f, ": ", r)
+		}
+	}()
+	ext := strings.ToLower(filepath.Ext(f))
+	mode := vbscanner.HTML_MODE
+	if ext == ".vbs" {
+		mode = vbscanner.VBS_MODE
+	}
+	if err := lex.Init(r, f, mode); err != nil {
+		log.Fatal(err)
+	}
+	lex.SetHTA(ext == ".hta")
+	for tok := range lex.All() {
+		fmt.Printf("%8s %-30s %-10s %v %#v\n", tok.Expanded, tok.Stack(), tok.Type, tok.Value, tok.Raw)
+	}
+	for _, err := range lex.Errors {
+		log.Print(err)
+	}
+	for _, err := range lex.LexErrors {
+		log.Print("PARSE ERROR ", err.Stack(), ": ", err.Msg)
+	}
+}
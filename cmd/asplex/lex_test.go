@@ -0,0 +1,24 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestAddVdir(t *testing.T) {
+	vdirs := make(map[string]string)
+	for _, s := range []string{`/shared=C:\shared`, "/lib=/srv/lib=old", "/shared=/srv/shared"} {
+		if err := addVdir(vdirs, s); err != nil {
+			t.Errorf("%q: %v", s, err)
+		}
+	}
+	want := map[string]string{"/shared": "/srv/shared", "/lib": "/srv/lib=old"}
+	if !maps.Equal(vdirs, want) {
+		t.Errorf("got %v, want %v", vdirs, want)
+	}
+	for _, s := range []string{"/shared", "=/srv/x", "/x=", ""} {
+		if err := addVdir(vdirs, s); err == nil {
+			t.Errorf("%q: no error", s)
+		}
+	}
+}
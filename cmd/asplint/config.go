@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configNames are the names of the configuration files looked for in the
+// root folder, in order.
+var configNames = []string{".asplint.yaml", ".asplint.yml", ".asplint.json"}
+
+// Config enables and disables rules and sets their severity. A setting is
+// "off", "on" for the rule's own severity, or a severity: "info", "warning"
+// or "error". For example:
+//
+//	rules:
+//	  stop: error
+//	  unused-param: off
+//	overrides:
+//	  - files: legacy
+//	    rules:
+//	      unused-local: off
+type Config struct {
+	Rules     map[string]string `yaml:"rules" json:"rules"`         // settings by rule ID
+	Overrides []Override        `yaml:"overrides" json:"overrides"` // settings for some files, applied in order after Rules
+
+	root string         // folder the file patterns are relative to
+	on   map[*Rule]bool // rules enabled by flags, whatever the settings
+}
+
+// Override holds the settings of the files matching a pattern.
+type Override struct {
+	// Files is a pattern, as used by path.Match, matched against the path of
+	// a file relative to the root folder, with slashes, against each of the
+	// folders in that path, and against the name of the file. So "legacy" or
+	// "admin/*" match all the files under those folders, and "*.inc" matches
+	// include files anywhere.
+	Files string            `yaml:"files" json:"files"`
+	Rules map[string]string `yaml:"rules" json:"rules"` // settings by rule ID
+}
+
+// readConfig reads the configuration in the file name, as JSON if it ends in
+// .json and as YAML otherwise. If name is empty, the first of configNames
+// found in root is read, or an empty configuration is returned.
+func readConfig(name, root string) (*Config, error) {
+	cfg := &Config{root: root, on: make(map[*Rule]bool)}
+	if name == "" {
+		for _, n := range configNames {
+			if _, err := os.Stat(filepath.Join(root, n)); err == nil {
+				name = filepath.Join(root, n)
+				break
+			}
+		}
+		if name == "" {
+			return cfg, nil
+		}
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(cfg)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(cfg); errors.Is(err, io.EOF) {
+			// no settings
+			err = nil
+		}
+	}
+	if err == nil {
+		err = cfg.check()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return cfg, nil
+}
+
+// check returns an error if the configuration names unknown rules, has
+// settings that are not valid, or has file patterns that are malformed.
+func (c *Config) check() error {
+	sets := []map[string]string{c.Rules}
+	for _, o := range c.Overrides {
+		if _, err := path.Match(o.Files, ""); err != nil || o.Files == "" {
+			return fmt.Errorf("invalid file pattern %q", o.Files)
+		}
+		sets = append(sets, o.Rules)
+	}
+	for _, set := range sets {
+		for id, v := range set {
+			r := lookupRule(id)
+			if r == nil {
+				return fmt.Errorf("unknown rule %q", id)
+			}
+			if _, _, err := apply(r, v, r.Severity, true); err != nil {
+				return fmt.Errorf("rule %q: %w", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// enable turns r on for all files.
+func (c *Config) enable(r *Rule) {
+	c.on[r] = true
+}
+
+// setting returns the severity of the rule r for the file f, and whether r
+// is enabled for it. If f is empty, the overrides are ignored.
+func (c *Config) setting(r *Rule, f string) (sev Severity, on bool) {
+	sev, on = r.Severity, !r.Disabled
+	if v, ok := c.Rules[r.ID]; ok {
+		sev, on, _ = apply(r, v, sev, on)
+	}
+	for _, o := range c.Overrides {
+		if v, ok := o.Rules[r.ID]; ok && f != "" && c.match(o.Files, f) {
+			sev, on, _ = apply(r, v, sev, on)
+		}
+	}
+	return sev, on || c.on[r]
+}
+
+// apply returns the severity of r and whether it is enabled, once the
+// setting v is applied to the severity sev and the state on.
+func apply(r *Rule, v string, sev Severity, on bool) (Severity, bool, error) {
+	switch strings.ToLower(v) {
+	case "off":
+		return sev, false, nil
+	case "on":
+		return r.Severity, true, nil
+	}
+	s, err := parseSeverity(v)
+	if err != nil {
+		return sev, on, err
+	}
+	return s, true, nil
+}
+
+// match returns true if the file f, its name, or one of the folders it is in
+// matches pattern.
+func (c *Config) match(pattern, f string) bool {
+	rel, err := filepath.Rel(c.root, f)
+	if err != nil {
+		rel = f
+	}
+	rel = filepath.ToSlash(rel)
+	if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+		return true
+	}
+	for p := rel; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
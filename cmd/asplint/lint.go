@@ -1,102 +1,298 @@
 package main
 
 import (
+	"bytes"
+	"cmp"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/ancientlore/vbscribble/globalasa"
+	"github.com/ancientlore/vbscribble/vbinclude"
 	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbparser"
 	"github.com/ancientlore/vbscribble/vbscanner"
+	"github.com/ancientlore/vbscribble/vbscope"
+	"github.com/ancientlore/vbscribble/vbwsf"
 )
 
+// lintExt holds the extensions of the files to check.
+var lintExt = map[string]bool{
+	".asp": true,
+	".asa": true,
+	".vbs": true,
+	".wsf": true,
+	".hta": true,
+}
+
 func main() {
 	var root string
 	var obj bool
 	var objNew bool
+	var checkAsa bool
+	var explicit bool
+	var config string
+	var listRules bool
 	flag.StringVar(&root, "root", ".", "Root folder to search")
 	flag.BoolVar(&obj, "obj", false, "Show COM objects used in each file")
 	flag.BoolVar(&objNew, "new", false, "Show objects created with new in each file")
+	flag.BoolVar(&checkAsa, "asa", false, "Check Application variables and static objects against global.asa in the root folder")
+	flag.BoolVar(&explicit, "explicit", false, "Report variables used without a declaration, even in files without Option Explicit")
+	flag.StringVar(&config, "config", "", "Configuration file, instead of .asplint.yaml, .asplint.yml or .asplint.json in the root folder")
+	flag.BoolVar(&listRules, "rules", false, "List the rules and exit")
 	flag.Parse()
 
+	cfg, err := readConfig(config, root)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if obj {
+		cfg.enable(lookupRule("object"))
+	}
+	if objNew {
+		cfg.enable(lookupRule("new-object"))
+	}
+	if listRules {
+		printRules(cfg)
+		return
+	}
+
+	l := linter{
+		cfg:      cfg,
+		res:      vbinclude.Resolver{Root: root},
+		explicit: explicit,
+		site:     make(map[string]any),
+	}
+	if checkAsa {
+		if l.asa, err = readGlobalAsa(root); err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			fmt.Println(err.Error())
 			return err
 		}
-		if !info.IsDir() && filepath.Ext(info.Name()) == ".asp" {
-			messages := make([]string, 0)
-			fil, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer fil.Close()
-			func(fil io.Reader, f string) {
+		if !info.IsDir() && lintExt[strings.ToLower(filepath.Ext(info.Name()))] {
+			l.problems = nil
+			l.check(path)
+			printProblems(path, l.problems)
+		}
+		return nil
+	})
+
+	// procedures and classes are used by any page that includes them, so
+	// they are reported once all pages are checked
+	l.problems = nil
+	l.finish()
+	var files []string
+	for _, pr := range l.problems {
+		if !slices.Contains(files, pr.Filename) {
+			files = append(files, pr.Filename)
+		}
+	}
+	for _, f := range files {
+		printProblems(f, slices.DeleteFunc(slices.Clone(l.problems), func(pr Problem) bool { return pr.Filename != f }))
+	}
+}
+
+// linter runs the rules over the files checked.
+type linter struct {
+	cfg      *Config
+	res      vbinclude.Resolver
+	asa      *globalasa.File
+	explicit bool
+	site     map[string]any // state kept across files by rules
+	problems []Problem      // problems found in the file being checked
+}
+
+// pass returns a pass of the rule r over the file f.
+func (l *linter) pass(r *Rule, f string) *Pass {
+	return &Pass{
+		Rule:     r,
+		Filename: f,
+		Resolver: &l.res,
+		Asa:      l.asa,
+		Explicit: l.explicit,
+		Site:     l.site,
+		report:   l.report,
+	}
+}
+
+// report records pr with the severity configured for its file, unless its
+// rule is disabled there.
+func (l *linter) report(pr Problem) {
+	sev, on := l.cfg.setting(pr.Rule, pr.Filename)
+	if !on {
+		return
+	}
+	pr.Severity = sev
+	l.problems = append(l.problems, pr)
+}
+
+// check runs the rules over the file f. A file that cannot be read is
+// reported as a syntax error.
+func (l *linter) check(f string) {
+	fil, err := os.Open(f)
+	if err != nil {
+		l.pass(lookupRule("syntax"), f).Reportf(vbscanner.Position{}, "%v", err)
+		return
+	}
+	defer fil.Close()
+	if !strings.EqualFold(filepath.Ext(f), ".wsf") {
+		src, err := io.ReadAll(fil)
+		if err != nil {
+			l.pass(lookupRule("syntax"), f).Reportf(vbscanner.Position{}, "%v", err)
+			return
+		}
+		var lex vblexer.Lex
+		lex.InitFile(bytes.NewReader(src), f)
+		l.tokens(&lex, f)
+		files, err := vbscope.ParsePage(&l.res, f)
+		l.program(f, files, err, nil)
+		return
+	}
+	wsf, err := vbwsf.Read(fil)
+	if err != nil {
+		l.pass(lookupRule("syntax"), f).Reportf(vbscanner.Position{}, "%v", err)
+		return
+	}
+	for _, job := range wsf.Jobs {
+		var files []*vbparser.File
+		var errs []error
+		for _, sc := range job.Scripts {
+			if sc.IsVBScript() {
 				var lex vblexer.Lex
-				defer func() {
-					if r := recover(); r != nil {
-						fmt.Println("*** ", f, " ***")
-						fmt.Println("PARSE ERROR:", lex.Line, ": ", r)
-						fmt.Println()
-					}
-				}()
-				lex.Init(fil, f, vbscanner.HTML_MODE)
-				creatingObj := false
-				newingObj := false
-				for k, t, v := lex.Lex(); k != vblexer.EOF; k, t, v = lex.Lex() {
-					switch k {
-					case vblexer.STATEMENT:
-						switch t {
-						case "Stop":
-							messages = append(messages, fmt.Sprintf("%d: Statement [Stop] should not be used in production code", lex.Line))
-						case "Execute", "Executeglobal":
-							messages = append(messages, fmt.Sprintf("%d: Statement [%s] is not recommended", lex.Line, t))
-						case "New":
-							newingObj = true
-						}
-					case vblexer.FUNCTION:
-						switch t {
-						case "Eval":
-							messages = append(messages, fmt.Sprintf("%d: Function [%s] is not recommended", lex.Line, t))
-						}
-					case vblexer.IDENTIFIER:
-						switch strings.ToLower(v) {
-						case "createobject", "server.createobject", "wscript.createobject":
-							creatingObj = true
-						default:
-							if creatingObj && obj {
-								messages = append(messages, fmt.Sprintf("%d: Using object [%s]", lex.Line, v))
-							}
-							if newingObj && objNew {
-								messages = append(messages, fmt.Sprintf("%d: New object [%s]", lex.Line, v))
-							}
-							creatingObj = false
-							newingObj = false
-						}
-					case vblexer.STRING:
-						if creatingObj && obj {
-							messages = append(messages, fmt.Sprintf("%d: Using object [%s]", lex.Line, v))
-						}
-						creatingObj = false
-						newingObj = false
-					case vblexer.CHAR:
-						// ! and @ appear as part of asp sections and html comments
-						if !strings.ContainsAny(v, "@!") {
-							messages = append(messages, fmt.Sprintf("%d: Unrecognized character [%s]", lex.Line, v))
-						}
-					}
+				sc.Init(&lex, f)
+				l.tokens(&lex, f)
+				sc.Init(&lex, f)
+				file, err := vbparser.Parse(&lex)
+				if err != nil {
+					errs = append(errs, err)
 				}
-				if len(messages) > 0 {
-					fmt.Println("*** ", f, " ***")
-					for _, m := range messages {
-						fmt.Println(m)
-					}
-					fmt.Println()
+				if file != nil {
+					files = append(files, file)
 				}
-			}(fil, path)
+			}
 		}
-		return nil
+		// the scripts of a job share their names
+		l.program(f, files, errors.Join(errs...), job)
+	}
+}
+
+// tokens runs the Token rules over the tokens read by lex from the file f.
+func (l *linter) tokens(lex *vblexer.Lex, f string) {
+	var passes []*Pass
+	for _, r := range rules {
+		if r.Token != nil {
+			p := l.pass(r, f)
+			p.Lex = lex
+			passes = append(passes, p)
+		}
+	}
+	var prev []vblexer.Token
+	for tok := range lex.All() {
+		for _, p := range passes {
+			p.prev = prev
+			p.Rule.Token(p, tok)
+		}
+		// a few tokens are enough to recognize Server.CreateObject(
+		prev = append([]vblexer.Token{tok}, prev[:min(len(prev), 3)]...)
+	}
+}
+
+// program runs the Program rules over files, the first of which is f, as
+// parsed with the errors in err. Files are the scripts of job in WSF files.
+func (l *linter) program(f string, files []*vbparser.File, err error, job *vbwsf.Job) {
+	// the objects of the job and of global.asa are created by the host
+	var objects []string
+	if job != nil {
+		for _, o := range job.Objects {
+			objects = append(objects, o.ID)
+		}
+	}
+	if l.asa != nil {
+		for _, o := range l.asa.Objects {
+			objects = append(objects, o.ID)
+		}
+	}
+	info := vbscope.ResolveObjects(objects, files...)
+	for _, r := range rules {
+		if r.Program != nil {
+			p := l.pass(r, f)
+			p.Files, p.Info, p.Err, p.Job = files, info, err, job
+			r.Program(p)
+		}
+	}
+}
+
+// finish runs the Finish rules once all files are checked.
+func (l *linter) finish() {
+	for _, r := range rules {
+		if r.Finish != nil {
+			r.Finish(l.pass(r, ""))
+		}
+	}
+}
+
+// printProblems prints the problems found checking the file f, in order of
+// position, those in f first.
+func printProblems(f string, problems []Problem) {
+	if len(problems) == 0 {
+		return
+	}
+	slices.SortStableFunc(problems, func(a, b Problem) int {
+		switch {
+		case a.Filename == b.Filename:
+			return cmp.Compare(a.Pos.Offset, b.Pos.Offset)
+		case a.Filename == f:
+			return -1
+		case b.Filename == f:
+			return 1
+		}
+		return strings.Compare(a.Filename, b.Filename)
 	})
+	fmt.Println("*** ", f, " ***")
+	for _, pr := range problems {
+		fmt.Println(pr.String(f))
+	}
+	fmt.Println()
+}
+
+// printRules prints the rules with their category, and the severity they
+// have unless overridden for some files, or off.
+func printRules(cfg *Config) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "RULE\tCATEGORY\tSEVERITY\tDESCRIPTION")
+	for _, r := range rules {
+		sev, on := cfg.setting(r, "")
+		s := strings.ToLower(sev.String())
+		if !on {
+			s = "off"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.ID, r.Category, s, r.Doc)
+	}
+	w.Flush()
+}
+
+// readGlobalAsa reads the global.asa file in dir, if there is one.
+func readGlobalAsa(dir string) (*globalasa.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if strings.EqualFold(e.Name(), "global.asa") {
+			return globalasa.ReadFile(filepath.Join(dir, e.Name()))
+		}
+	}
+	return nil, nil
 }
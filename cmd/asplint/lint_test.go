@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vbinclude"
+)
+
+// lint checks the file name among files, after reading global.asa if there
+// is one, and returns the problems found in that file as rule IDs with their
+// line, like "stop:3", in order.
+func lint(t *testing.T, files map[string]string, name string, explicit bool) []string {
+	t.Helper()
+	root := t.TempDir()
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := linter{
+		cfg:      &Config{root: root, on: make(map[*Rule]bool)},
+		res:      vbinclude.Resolver{Root: root},
+		explicit: explicit,
+		site:     make(map[string]any),
+	}
+	if _, ok := files["global.asa"]; ok {
+		asa, err := readGlobalAsa(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.asa = asa
+	}
+	path := filepath.Join(root, name)
+	l.check(path)
+	l.finish()
+	var list []string
+	for _, pr := range l.problems {
+		if pr.Filename == path {
+			list = append(list, fmt.Sprintf("%s:%d", pr.Rule.ID, pr.Pos.Line))
+		}
+	}
+	slices.Sort(list)
+	return list
+}
+
+const asa = `<object runat="server" scope="Application" id="MyConn" progid="ADODB.Connection"></object>
+<object runat="server" scope="Session" id="Cart" progid="Shop.Cart"></object>
+<script language="VBScript" runat="server">
+Sub Application_OnStart
+	Application("Count") = 0
+End Sub
+</script>
+`
+
+const job = `<job id="main">
+<object id="fso" progid="Scripting.FileSystemObject"/>
+<script language="VBScript">
+Option Explicit
+WScript.Echo fso.FileExists("x")
+WScript.Echo shell.CurrentDirectory
+</script>
+</job>
+`
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string // files of the site, with the file checked first
+		explicit bool
+		want     string
+	}{
+		{"clean", page("<%\nDim x\nx = 1\nResponse.Write x\n%>"), false, ""},
+		{"stop", page("<%\nStop\n%>"), false, "stop:2"},
+		{"execute", page("<%\nExecute \"x = 1\"\nExecuteGlobal \"y = 2\"\n%>"), false, "execute:2 execute:3"},
+		{"eval", page("<%\nResponse.Write Eval(\"1 + 1\")\n%>"), false, "eval:2"},
+		{"syntax", page("<%\nx = (1\n%>"), false, "syntax:2"},
+		{"missing include", page("<!-- #include file=\"none.asp\" -->"), false, "missing-include:1"},
+		{"explicit", page("<% Option Explicit\nx = 1\nResponse.Write x\n%>"), false, "explicit:2 explicit:3"},
+		{"explicit flag", page("<%\nx = 1\nResponse.Write x\n%>"), true, "explicit:2 explicit:3"},
+		{"no explicit", page("<%\nx = 1\nResponse.Write x\n%>"), false, ""},
+		{"unused local", page("<%\nSub Foo\nDim a, b\nb = 1\nEnd Sub\nFoo\n%>"), false, "unused-local:3 unused-local:3"},
+		{"unused param", page("<%\nSub Foo(a, b)\nResponse.Write a\nEnd Sub\nFoo 1, 2\n%>"), false, "unused-param:2"},
+		{"unused proc", page("<%\nSub Foo\nEnd Sub\nSub Bar\nEnd Sub\nBar\n%>"), false, "unused-proc:2"},
+		{"event handler", page("<%\nSub Window_OnLoad\nEnd Sub\n%>"), false, ""},
+		{"unused include", page("<!-- #include file=\"inc.asp\" -->", "<% Sub Helper\nEnd Sub %>"), false, "unused-include:1"},
+		{"used include", page("<!-- #include file=\"inc.asp\" -->\n<% Helper %>", "<% Sub Helper\nEnd Sub %>"), false, ""},
+		{"html include", page("<!-- #include file=\"inc.asp\" -->", "<p>footer</p>"), false, ""},
+		{"nested include", page("<% If a Then %>\n<!-- #include file=\"inc.asp\" -->\n<% End If %>", "<% Sub Helper\nEnd Sub %>"), false, "unused-include:2"},
+		{"global.asa objects", map[string]string{
+			"page.asp":   "<% Option Explicit\nMyConn.Open\nCart.Add 1\nOther.Add 1\n%>",
+			"global.asa": asa,
+		}, false, "explicit:4"},
+		{"no global.asa", page("<% Option Explicit\nMyConn.Open\n%>"), false, "explicit:2"},
+		{"application var", map[string]string{
+			"page.asp":   "<%\nResponse.Write Application(\"Count\")\nResponse.Write Application(\"Total\")\n%>",
+			"global.asa": asa,
+		}, false, "application-var:3"},
+		{"job objects", map[string]string{"job.wsf": job}, false, "explicit:6"},
+	}
+	for _, tt := range tests {
+		name := "page.asp"
+		if _, ok := tt.files[name]; !ok {
+			name = "job.wsf"
+		}
+		got := strings.Join(lint(t, tt.files, name, tt.explicit), " ")
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// page returns the files of a site with page.asp, and inc.asp if given.
+func page(text string, inc ...string) map[string]string {
+	files := map[string]string{"page.asp": text}
+	if len(inc) > 0 {
+		files["inc.asp"] = inc[0]
+	}
+	return files
+}
+
+func TestUnreadable(t *testing.T) {
+	got := strings.Join(lint(t, page("<% x %>"), "missing.asp", false), " ")
+	if got != "syntax:0" {
+		t.Errorf("got %q, want syntax:0", got)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	stop, local := lookupRule("stop"), lookupRule("unused-local")
+	obj := lookupRule("object")
+	cfg := &Config{
+		Rules: map[string]string{"stop": "error", "unused-local": "off"},
+		Overrides: []Override{
+			{Files: "legacy", Rules: map[string]string{"stop": "off"}},
+			{Files: "*.inc", Rules: map[string]string{"unused-local": "on"}},
+			{Files: "admin/*", Rules: map[string]string{"stop": "info"}},
+		},
+		root: "/site",
+		on:   make(map[*Rule]bool),
+	}
+	if err := cfg.check(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		rule *Rule
+		file string
+		sev  Severity
+		on   bool
+	}{
+		{stop, "/site/page.asp", Error, true},
+		{stop, "/site/legacy/page.asp", Error, false},
+		{stop, "/site/legacy/old/page.asp", Error, false},
+		{stop, "/site/admin/page.asp", Info, true},
+		{stop, "", Error, true},
+		{local, "/site/page.asp", Warning, false},
+		{local, "/site/lib/util.inc", Warning, true},
+		{obj, "/site/page.asp", Info, false},
+	}
+	for _, tt := range tests {
+		sev, on := cfg.setting(tt.rule, tt.file)
+		if sev != tt.sev || on != tt.on {
+			t.Errorf("%s in %q: got %v, %v; want %v, %v", tt.rule.ID, tt.file, sev, on, tt.sev, tt.on)
+		}
+	}
+	cfg.enable(obj)
+	if _, on := cfg.setting(obj, "/site/page.asp"); !on {
+		t.Errorf("object: not enabled")
+	}
+
+	bad := []*Config{
+		{Rules: map[string]string{"no-such-rule": "on"}},
+		{Rules: map[string]string{"stop": "fatal"}},
+		{Overrides: []Override{{Files: "[", Rules: map[string]string{"stop": "off"}}}},
+		{Overrides: []Override{{Rules: map[string]string{"stop": "off"}}}},
+	}
+	for i, c := range bad {
+		if err := c.check(); err == nil {
+			t.Errorf("bad config %d: no error", i)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/ancientlore/vbscribble/vbinclude"
+	"github.com/ancientlore/vbscribble/vbparser"
+	"github.com/ancientlore/vbscribble/vbscanner"
+	"github.com/ancientlore/vbscribble/vbscope"
+)
+
+func init() {
+	register(
+		&Rule{
+			ID:       "syntax",
+			Doc:      "syntax errors, and tokens the lexer rejects, like invalid date literals",
+			Category: Syntax,
+			Severity: Error,
+			Program: func(p *Pass) {
+				eachError(p.Err, func(err error) {
+					switch err := err.(type) {
+					case *vbinclude.Error:
+					case vbparser.ErrorList:
+						// errors in included files are reported when those files are checked
+						for _, e := range err {
+							if e.Filename == p.Filename {
+								p.Reportf(e.Pos, "%s", e.Msg)
+							}
+						}
+					default:
+						p.Reportf(vbscanner.Position{}, "%v", err)
+					}
+				})
+			},
+		},
+		&Rule{
+			ID:       "missing-include",
+			Doc:      "#include directives naming files that cannot be read",
+			Category: Correctness,
+			Severity: Error,
+			Program: func(p *Pass) {
+				eachError(p.Err, func(err error) {
+					if err, ok := err.(*vbinclude.Error); ok && err.Filename == p.Filename {
+						p.Reportf(err.Pos, "Include [%s] cannot be read: %v", err.Path, err.Err)
+					}
+				})
+			},
+		},
+		&Rule{
+			ID:       "explicit",
+			Doc:      "variables used without a declaration, in pages with Option Explicit or with -explicit",
+			Category: Correctness,
+			Severity: Error,
+			Program:  undeclared,
+		},
+	)
+}
+
+// eachError calls fn with each of the errors joined in err.
+func eachError(err error, fn func(error)) {
+	switch e := err.(type) {
+	case nil:
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			eachError(err, fn)
+		}
+	default:
+		fn(err)
+	}
+}
+
+// undeclared reports the uses of variables that are not declared in the
+// program, if one of its files has Option Explicit or p.Explicit is set.
+// Uses in other files, like includes, are reported in those files.
+func undeclared(p *Pass) {
+	if !p.Explicit && !slices.ContainsFunc(p.Files, hasOptionExplicit) {
+		return
+	}
+	var refs []vbscope.Ref
+	var walk func(s *vbscope.Scope)
+	walk = func(s *vbscope.Scope) {
+		for _, sym := range s.Symbols() {
+			if sym.Implicit {
+				refs = append(refs, sym.Uses...)
+			}
+		}
+		for _, c := range s.Children {
+			walk(c)
+		}
+	}
+	walk(p.Info.Page)
+	slices.SortStableFunc(refs, func(a, b vbscope.Ref) int {
+		if a.File != b.File {
+			return slices.Index(p.Files, a.File) - slices.Index(p.Files, b.File)
+		}
+		return a.Ident.Pos().Offset - b.Ident.Pos().Offset
+	})
+	for _, ref := range refs {
+		p.ReportFilef(ref.File.Name, ref.Ident.Pos(), "Variable [%s] is not declared", ref.Ident.Name)
+	}
+}
+
+// hasOptionExplicit returns true if f has an Option Explicit statement.
+func hasOptionExplicit(f *vbparser.File) bool {
+	return slices.ContainsFunc(f.Body, func(s vbparser.Stmt) bool {
+		_, ok := s.(*vbparser.OptionExplicitStmt)
+		return ok
+	})
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ancientlore/vbscribble/globalasa"
+	"github.com/ancientlore/vbscribble/vbinclude"
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbparser"
+	"github.com/ancientlore/vbscribble/vbscanner"
+	"github.com/ancientlore/vbscribble/vbscope"
+	"github.com/ancientlore/vbscribble/vbwsf"
+)
+
+//go:generate stringer -type=Severity
+
+// Severity is how serious a problem is.
+type Severity int
+
+// Severities
+const (
+	Info    Severity = iota // worth knowing, like the COM objects a page uses
+	Warning                 // likely a mistake or dead code
+	Error                   // fails at run time, or hides bugs that do
+)
+
+// parseSeverity returns the severity with the given name, in any case.
+func parseSeverity(s string) (Severity, error) {
+	for sev := Info; sev <= Error; sev++ {
+		if strings.EqualFold(s, sev.String()) {
+			return sev, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q", s)
+}
+
+// Category groups related rules.
+type Category string
+
+// Categories
+const (
+	Syntax      Category = "syntax"      // code that cannot be parsed
+	Correctness Category = "correctness" // code that does not do what was meant
+	Security    Category = "security"    // code that runs strings as code
+	Unused      Category = "unused"      // dead code
+	Objects     Category = "objects"     // COM objects and classes in use
+)
+
+// Rule is a check made by asplint. A rule visits the tokens of each file,
+// the parsed program of each file, or both, and reports the problems it
+// finds through the Pass it is given.
+type Rule struct {
+	ID       string   // name used in the configuration and in messages, like "stop"
+	Doc      string   // one-line description
+	Category Category // kind of problems found
+	Severity Severity // severity of the problems, unless configured otherwise
+	Disabled bool     // problems are only reported when enabled by a flag or the configuration
+
+	// Token is called with each token of the file, if not nil. Tokens of
+	// WSF files are those of their VBScript scripts.
+	Token func(p *Pass, tok vblexer.Token)
+
+	// Program is called once for each file, if not nil, after the file is
+	// parsed along with the files it includes.
+	Program func(p *Pass)
+
+	// Finish is called once after all files are checked, if not nil, for
+	// rules that gather what is used across the site.
+	Finish func(p *Pass)
+}
+
+// rules holds the rules asplint knows, in the order they are listed.
+var rules []*Rule
+
+// register adds r to the rules asplint knows.
+func register(r ...*Rule) {
+	rules = append(rules, r...)
+}
+
+// lookupRule returns the rule with the given ID, or nil.
+func lookupRule(id string) *Rule {
+	for _, r := range rules {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// Problem is a problem found by a rule.
+type Problem struct {
+	Rule     *Rule
+	Severity Severity
+	Filename string
+	Pos      vbscanner.Position
+	Msg      string
+}
+
+// String returns the problem as printed for the file f: with its position,
+// severity, message and rule. The file name is given when it is not f.
+func (pr Problem) String(f string) string {
+	pos := pr.Pos.String()
+	if pr.Filename != f {
+		pos = pr.Filename + ":" + pos
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", pos, strings.ToLower(pr.Severity.String()), pr.Msg, pr.Rule.ID)
+}
+
+// Pass is given to a rule with what it checks and a way to report problems.
+type Pass struct {
+	Rule     *Rule
+	Filename string              // file being checked
+	Lex      *vblexer.Lex        // lexer reading the file, for Token
+	Files    []*vbparser.File    // the file and the files it includes, for Program
+	Info     *vbscope.Info       // scopes and symbols of Files, for Program
+	Err      error               // errors found parsing Files, for Program
+	Job      *vbwsf.Job          // job the scripts in Files belong to, for WSF files
+	Resolver *vbinclude.Resolver // resolver of the include paths
+	Asa      *globalasa.File     // global.asa, if checked
+	Explicit bool                // report undeclared variables even without Option Explicit
+	Site     map[string]any      // state kept across files by rules, by rule ID
+
+	prev   []vblexer.Token // tokens before the current one, latest first
+	report func(Problem)
+}
+
+// Prev returns the token i tokens before the current one, starting at 0,
+// or an empty token.
+func (p *Pass) Prev(i int) vblexer.Token {
+	if i < len(p.prev) {
+		return p.prev[i]
+	}
+	return vblexer.Token{}
+}
+
+// Reportf reports a problem at pos in the file being checked.
+func (p *Pass) Reportf(pos vbscanner.Position, format string, args ...interface{}) {
+	p.ReportFilef(p.Filename, pos, format, args...)
+}
+
+// ReportFilef reports a problem at pos in the file f, like one in a file
+// included by the file being checked.
+func (p *Pass) ReportFilef(f string, pos vbscanner.Position, format string, args ...interface{}) {
+	p.report(Problem{Rule: p.Rule, Severity: p.Rule.Severity, Filename: f, Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+)
+
+func init() {
+	register(
+		&Rule{
+			ID:       "stop",
+			Doc:      "uses of Stop, which should not be used in production code",
+			Category: Correctness,
+			Severity: Warning,
+			Token: func(p *Pass, tok vblexer.Token) {
+				if tok.Type == vblexer.STATEMENT && tok.Value == "Stop" {
+					p.Reportf(tok.Start, "Statement [Stop] should not be used in production code")
+				}
+			},
+		},
+		&Rule{
+			ID:       "execute",
+			Doc:      "uses of Execute and ExecuteGlobal, which run strings as code",
+			Category: Security,
+			Severity: Warning,
+			Token: func(p *Pass, tok vblexer.Token) {
+				if tok.Type == vblexer.STATEMENT && (tok.Value == "Execute" || tok.Value == "ExecuteGlobal") {
+					p.Reportf(tok.Start, "Statement [%s] is not recommended", tok.Value)
+				}
+			},
+		},
+		&Rule{
+			ID:       "eval",
+			Doc:      "calls to Eval, which runs strings as code",
+			Category: Security,
+			Severity: Warning,
+			Token: func(p *Pass, tok vblexer.Token) {
+				if tok.Type == vblexer.FUNCTION && tok.Value == "Eval" {
+					p.Reportf(tok.Start, "Function [%s] is not recommended", tok.Value)
+				}
+			},
+		},
+		&Rule{
+			ID:       "unknown-char",
+			Doc:      "characters that are not part of VBScript",
+			Category: Syntax,
+			Severity: Warning,
+			Token: func(p *Pass, tok vblexer.Token) {
+				// ! appears as part of html comments
+				if tok.Type == vblexer.CHAR && !strings.Contains(tok.Raw, "!") {
+					p.Reportf(tok.Start, "Unrecognized character [%s]", tok.Raw)
+				}
+			},
+		},
+		&Rule{
+			ID:       "object",
+			Doc:      "uses of COM objects created with CreateObject, declared in WSF jobs, or declared in global.asa",
+			Category: Objects,
+			Severity: Info,
+			Disabled: true,
+			Token: func(p *Pass, tok vblexer.Token) {
+				switch tok.Type {
+				case vblexer.IDENTIFIER, vblexer.INTRINSIC_OBJECT, vblexer.INTRINSIC_MEMBER, vblexer.STRING:
+					if afterCreateObject(p) {
+						p.Reportf(tok.Start, "Using object [%s]", tok.Raw)
+					}
+					if tok.Type != vblexer.STRING && p.Asa != nil && p.Prev(0).Type != vblexer.FIELD_SEP {
+						if o := p.Asa.Object(tok.Raw); o != nil {
+							progID := o.ProgID
+							if progID == "" {
+								progID = o.ClassID
+							}
+							p.Reportf(tok.Start, "Using %s object [%s] declared as [%s] in global.asa", o.Scope, progID, o.ID)
+						}
+					}
+				}
+			},
+			Program: func(p *Pass) {
+				if p.Job == nil {
+					return
+				}
+				for _, o := range p.Job.Objects {
+					p.Reportf(o.Pos, "Using object [%s%s]", o.ProgID, o.ClassID)
+				}
+			},
+		},
+		&Rule{
+			ID:       "new-object",
+			Doc:      "classes instantiated with New",
+			Category: Objects,
+			Severity: Info,
+			Disabled: true,
+			Token: func(p *Pass, tok vblexer.Token) {
+				switch tok.Type {
+				case vblexer.IDENTIFIER, vblexer.INTRINSIC_OBJECT, vblexer.INTRINSIC_MEMBER:
+					if prev := p.Prev(0); prev.Type == vblexer.STATEMENT && prev.Value == "New" {
+						p.Reportf(tok.Start, "New object [%s]", tok.Raw)
+					}
+				}
+			},
+		},
+		&Rule{
+			ID:       "application-var",
+			Doc:      "uses of Application variables that are not set in global.asa, when it is checked",
+			Category: Correctness,
+			Severity: Warning,
+			Token: func(p *Pass, tok vblexer.Token) {
+				if p.Asa == nil || p.Prev(0).Type == vblexer.FIELD_SEP || !strings.EqualFold(tok.Raw, "Application") {
+					return
+				}
+				switch tok.Type {
+				case vblexer.IDENTIFIER, vblexer.INTRINSIC_OBJECT, vblexer.INTRINSIC_MEMBER:
+				default:
+					return
+				}
+				i := 0
+				if dot, m := p.Lex.Peek(0), p.Lex.Peek(1); dot.Type == vblexer.FIELD_SEP && strings.EqualFold(m.Raw, "Contents") {
+					i = 2
+				}
+				lp, key, rp := p.Lex.Peek(i), p.Lex.Peek(i+1), p.Lex.Peek(i+2)
+				if lp.Type == vblexer.PAREN_OPEN && key.Type == vblexer.STRING && rp.Type == vblexer.PAREN_CLOSE {
+					if p.Asa.Application[strings.ToLower(key.Raw)] == nil {
+						p.Reportf(key.Start, "Application variable [%s] is not set in global.asa", key.Raw)
+					}
+				}
+			},
+		},
+	)
+}
+
+// afterCreateObject returns true if the current token is the first name or
+// string after the CreateObject method of Server or WScript.
+func afterCreateObject(p *Pass) bool {
+	i := 0
+	if p.Prev(0).Type == vblexer.PAREN_OPEN {
+		i = 1
+	}
+	return isCreateObject(p.Prev(i+2), p.Prev(i+1), p.Prev(i))
+}
+
+// isCreateObject returns true if tok is the CreateObject method of Server or
+// WScript, given the two tokens before it.
+func isCreateObject(before, last, tok vblexer.Token) bool {
+	if !strings.EqualFold(tok.Raw, "CreateObject") {
+		return false
+	}
+	if last.Type != vblexer.FIELD_SEP {
+		return true
+	}
+	return (before.Type == vblexer.IDENTIFIER || before.Type == vblexer.INTRINSIC_OBJECT) && (strings.EqualFold(before.Raw, "Server") || strings.EqualFold(before.Raw, "WScript"))
+}
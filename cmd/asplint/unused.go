@@ -0,0 +1,238 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vbparser"
+	"github.com/ancientlore/vbscribble/vbscanner"
+	"github.com/ancientlore/vbscribble/vbscope"
+)
+
+func init() {
+	register(
+		&Rule{
+			ID:       "unused-local",
+			Doc:      "variables and constants of procedures that are never used, or assigned but never read",
+			Category: Unused,
+			Severity: Warning,
+			Program: func(p *Pass) {
+				eachLocal(p, func(sym *vbscope.Symbol) {
+					switch {
+					case sym.Kind == vbscope.Parameter:
+					case len(sym.Uses) == 0:
+						p.Reportf(sym.Decls[0].Pos(), "%s [%s] is not used", sym.Kind, sym.Name)
+					case sym.Kind == vbscope.Variable && !isRead(sym):
+						p.Reportf(sym.Decls[0].Pos(), "Variable [%s] is assigned but never read", sym.Name)
+					}
+				})
+			},
+		},
+		&Rule{
+			ID:       "unused-param",
+			Doc:      "parameters of procedures that are never used",
+			Category: Unused,
+			Severity: Warning,
+			Program: func(p *Pass) {
+				eachLocal(p, func(sym *vbscope.Symbol) {
+					if sym.Kind == vbscope.Parameter && len(sym.Uses) == 0 {
+						p.Reportf(sym.Decls[0].Pos(), "Parameter [%s] is not used", sym.Name)
+					}
+				})
+			},
+		},
+		&Rule{
+			ID:       "unused-include",
+			Doc:      "#include directives of files that contribute nothing to the page",
+			Category: Unused,
+			Severity: Warning,
+			Program:  unusedIncludes,
+		},
+		&Rule{
+			ID:       "unused-proc",
+			Doc:      "procedures and classes that no page uses",
+			Category: Unused,
+			Severity: Warning,
+			Program: func(p *Pass) {
+				sitewide(p).add(p.Info)
+			},
+			Finish: func(p *Pass) {
+				for _, d := range sitewide(p).list {
+					if !d.used {
+						p.ReportFilef(d.file, d.pos, "%s [%s] is not used", d.kind, d.name)
+					}
+				}
+			},
+		},
+	)
+}
+
+// eachLocal calls fn with each declared parameter, variable and constant of
+// the procedures in the file being checked.
+func eachLocal(p *Pass, fn func(sym *vbscope.Symbol)) {
+	var walk func(s *vbscope.Scope)
+	walk = func(s *vbscope.Scope) {
+		if s.Kind == vbscope.ProcScope && s.File.Name == p.Filename {
+			for _, sym := range s.Symbols() {
+				if !sym.Implicit {
+					fn(sym)
+				}
+			}
+		}
+		for _, c := range s.Children {
+			walk(c)
+		}
+	}
+	walk(p.Info.Page)
+}
+
+// isRead returns true if sym is used other than by assigning to it.
+func isRead(sym *vbscope.Symbol) bool {
+	for _, ref := range sym.Uses {
+		if !ref.Write {
+			return true
+		}
+	}
+	return false
+}
+
+// unusedIncludes reports the #include directives of the page that
+// contribute nothing to it: the included file, with the files it includes,
+// has no HTML or code that runs, and declares nothing used outside of them.
+func unusedIncludes(p *Pass) {
+	files, r, f := p.Files, p.Resolver, p.Filename
+	if len(files) == 0 || files[0].Name != f {
+		return
+	}
+	byName := make(map[string]*vbparser.File)
+	for _, file := range files {
+		byName[file.Name] = file
+	}
+	included := func(file *vbparser.File) (list []*vbparser.File) {
+		for _, inc := range vbscope.Includes(file) {
+			if name, err := r.Resolve(file.Name, inc.Virtual, inc.Path); err == nil && byName[name] != nil {
+				list = append(list, byName[name])
+			}
+		}
+		return list
+	}
+	for _, inc := range vbscope.Includes(files[0]) {
+		name, err := r.Resolve(f, inc.Virtual, inc.Path)
+		if err != nil || byName[name] == nil {
+			// reported by missing-include
+			continue
+		}
+		// the included file and the files it includes
+		sub := map[*vbparser.File]bool{byName[name]: true}
+		for list := []*vbparser.File{byName[name]}; len(list) > 0; list = list[1:] {
+			for _, file := range included(list[0]) {
+				if !sub[file] {
+					sub[file] = true
+					list = append(list, file)
+				}
+			}
+		}
+		if !contributes(sub, p.Info) {
+			p.Reportf(inc.Pos(), "Include [%s] is not used", inc.Path)
+		}
+	}
+}
+
+// contributes returns true if the files in sub have HTML or code that runs,
+// or declare names used in other files.
+func contributes(sub map[*vbparser.File]bool, info *vbscope.Info) bool {
+	for file := range sub {
+		for _, s := range file.Body {
+			switch s := s.(type) {
+			case *vbparser.DimStmt, *vbparser.ConstStmt, *vbparser.ProcDecl, *vbparser.ClassDecl,
+				*vbparser.OptionExplicitStmt, *vbparser.IncludeStmt, *vbparser.DirectiveStmt:
+			case *vbparser.HTMLStmt:
+				if strings.TrimSpace(s.Text) != "" {
+					return true
+				}
+			default:
+				return true
+			}
+		}
+	}
+	for _, sym := range info.Page.Symbols() {
+		if sym.Implicit || !sub[sym.File] {
+			continue
+		}
+		for _, ref := range sym.Uses {
+			if !sub[ref.File] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// procs tracks the procedures and classes declared at page level across the
+// files checked, and whether any page uses them.
+type procs struct {
+	list  []*proc
+	byKey map[string]*proc
+}
+
+// proc is a procedure or class declared at page level.
+type proc struct {
+	file string             // file of the declaration
+	pos  vbscanner.Position // position of the declaration
+	kind vbscope.Kind
+	name string
+	used bool
+}
+
+// sitewide returns the procedures tracked by the rule of p across files.
+func sitewide(p *Pass) *procs {
+	s, ok := p.Site[p.Rule.ID].(*procs)
+	if !ok {
+		s = &procs{byKey: make(map[string]*proc)}
+		p.Site[p.Rule.ID] = s
+	}
+	return s
+}
+
+// add records the procedures and classes declared at page level in the
+// program described by info, and those that it uses.
+func (p *procs) add(info *vbscope.Info) {
+	for _, sym := range info.Page.Symbols() {
+		switch sym.Kind {
+		case vbscope.Sub, vbscope.Function, vbscope.Class:
+		default:
+			continue
+		}
+		if isEventHandler(sym.Name) {
+			continue
+		}
+		k := fileKey(sym.File.Name) + "\x00" + strings.ToLower(sym.Name)
+		d := p.byKey[k]
+		if d == nil {
+			d = &proc{file: sym.File.Name, pos: sym.Decls[0].Pos(), kind: sym.Kind, name: sym.Name}
+			p.byKey[k] = d
+			p.list = append(p.list, d)
+		}
+		// assigning the name of a function sets its result
+		if isRead(sym) {
+			d.used = true
+		}
+	}
+}
+
+// isEventHandler returns true if name is that of a procedure called by the
+// host when an event occurs, like Session_OnStart, Window_OnLoad or
+// OnTransactionCommit.
+func isEventHandler(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Index(name, "_on") > 0 || strings.HasPrefix(name, "ontransaction")
+}
+
+// fileKey returns the name of a file for recognizing it when included by
+// several pages.
+func fileKey(name string) string {
+	if abs, err := filepath.Abs(name); err == nil {
+		return abs
+	}
+	return filepath.Clean(name)
+}
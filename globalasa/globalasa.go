@@ -0,0 +1,198 @@
+// Package globalasa reads global.asa files, which declare the event handlers,
+// static objects and shared variables of an ASP application.
+package globalasa
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vbparser"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// Event names
+const (
+	ApplicationOnStart = "Application_OnStart"
+	ApplicationOnEnd   = "Application_OnEnd"
+	SessionOnStart     = "Session_OnStart"
+	SessionOnEnd       = "Session_OnEnd"
+)
+
+// events maps the lower case event names to their usual spelling.
+var events = map[string]string{
+	"application_onstart": ApplicationOnStart,
+	"application_onend":   ApplicationOnEnd,
+	"session_onstart":     SessionOnStart,
+	"session_onend":       SessionOnEnd,
+}
+
+// File is a parsed global.asa file.
+type File struct {
+	Name        string
+	AST         *vbparser.File
+	Events      []*Event             // event handlers, in source order
+	Objects     []*Object            // static objects, in source order
+	Application map[string]*Variable // Application variables set in the file, by lower case name
+	Session     map[string]*Variable // Session variables set in the file, by lower case name
+}
+
+// Event is a handler for one of the application or session events.
+type Event struct {
+	Name string // one of the event names, like Application_OnStart
+	Proc *vbparser.ProcDecl
+}
+
+// Object is a static object declared with <object runat="server">.
+type Object struct {
+	Pos     vbscanner.Position
+	ID      string
+	Scope   string // Application or Session
+	ProgID  string
+	ClassID string
+}
+
+// Variable is an Application or Session variable, like Application("name").
+type Variable struct {
+	Name string             // name as first written
+	Pos  vbscanner.Position // position of the first assignment
+}
+
+// ReadFile reads and parses the global.asa file in filename. Like Parse, it
+// may return a File along with the syntax errors in it.
+func ReadFile(filename string) (*File, error) {
+	fil, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fil.Close()
+	return Parse(filename, fil)
+}
+
+// Parse parses a global.asa file read from src. If the file has syntax
+// errors, the File holds what could be parsed and err lists the errors.
+func Parse(filename string, src io.Reader) (*File, error) {
+	ast, err := vbparser.ParseFile(filename, src, vbscanner.HTML_MODE)
+	if ast == nil {
+		return nil, err
+	}
+	f := &File{
+		Name:        filename,
+		AST:         ast,
+		Application: make(map[string]*Variable),
+		Session:     make(map[string]*Variable),
+	}
+	for _, s := range ast.Body {
+		switch s := s.(type) {
+		case *vbparser.HTMLStmt:
+			f.findObjects(s)
+		case *vbparser.ProcDecl:
+			if name, ok := events[strings.ToLower(s.Name.Name)]; ok && s.Kind == vbparser.SubProc {
+				f.Events = append(f.Events, &Event{Name: name, Proc: s})
+			}
+		}
+	}
+	vbparser.Inspect(ast, func(n vbparser.Node) bool {
+		if a, ok := n.(*vbparser.AssignStmt); ok {
+			f.addVariable(a.Target)
+		}
+		return true
+	})
+	return f, err
+}
+
+// Event returns the handler for the named event, or nil if there is none.
+func (f *File) Event(name string) *Event {
+	for _, e := range f.Events {
+		if strings.EqualFold(e.Name, name) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Object returns the static object with the given ID, or nil if there is none.
+func (f *File) Object(id string) *Object {
+	for _, o := range f.Objects {
+		if strings.EqualFold(o.ID, id) {
+			return o
+		}
+	}
+	return nil
+}
+
+var (
+	objectRE = regexp.MustCompile(`(?i)<object\b([^>]*)>`)
+	attrRE   = regexp.MustCompile(`([\w:-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+	runatRE  = regexp.MustCompile(`(?i)\brunat\s*=\s*["']?server\b`)
+)
+
+// findObjects adds the server-side <object> tags in the HTML to the file.
+func (f *File) findObjects(s *vbparser.HTMLStmt) {
+	for _, m := range objectRE.FindAllStringSubmatchIndex(s.Text, -1) {
+		text := s.Text[m[2]:m[3]]
+		if !runatRE.MatchString(text) {
+			continue
+		}
+		attrs := make(map[string]string)
+		for _, a := range attrRE.FindAllStringSubmatch(text, -1) {
+			v := a[2]
+			if strings.HasPrefix(v, `"`) || strings.HasPrefix(v, "'") {
+				v = v[1 : len(v)-1]
+			}
+			attrs[strings.ToLower(a[1])] = v
+		}
+		scope := attrs["scope"]
+		switch strings.ToLower(scope) {
+		case "application":
+			scope = "Application"
+		case "session":
+			scope = "Session"
+		}
+		f.Objects = append(f.Objects, &Object{
+			Pos:     s.Pos().Advance(s.Text[:m[0]]),
+			ID:      attrs["id"],
+			Scope:   scope,
+			ProgID:  attrs["progid"],
+			ClassID: attrs["classid"],
+		})
+	}
+}
+
+// addVariable records the Application or Session variable assigned by
+// Application("name") = value or Application.Contents("name") = value.
+func (f *File) addVariable(target vbparser.Expr) {
+	call, ok := target.(*vbparser.CallOrIndexExpr)
+	if !ok || len(call.Args) != 1 {
+		return
+	}
+	lit, ok := call.Args[0].(*vbparser.BasicLit)
+	if !ok {
+		return
+	}
+	name, ok := lit.Value.(string)
+	if !ok {
+		return
+	}
+	fun := call.Fun
+	if m, ok := fun.(*vbparser.MemberExpr); ok && strings.EqualFold(m.Name.Name, "Contents") {
+		fun = m.X
+	}
+	id, ok := fun.(*vbparser.Ident)
+	if !ok {
+		return
+	}
+	var vars map[string]*Variable
+	switch strings.ToLower(id.Name) {
+	case "application":
+		vars = f.Application
+	case "session":
+		vars = f.Session
+	default:
+		return
+	}
+	if _, ok := vars[strings.ToLower(name)]; !ok {
+		vars[strings.ToLower(name)] = &Variable{Name: name, Pos: target.Pos()}
+	}
+}
@@ -0,0 +1,104 @@
+package globalasa
+
+import (
+	"strings"
+	"testing"
+)
+
+const asa = `<object runat="server" scope="Application" id="Conn" progid="ADODB.Connection"></object>
+<OBJECT RUNAT=Server SCOPE=session ID=Cart CLASSID="clsid:00000000-0000-0000-0000-000000000000"></OBJECT>
+<object id="Client" progid="Client.Thing"></object>
+<script language="VBScript" runat="server">
+Sub Application_OnStart
+	Application("Count") = 0
+	Application.Contents("Name") = "site"
+	Application("count") = 1
+End Sub
+Sub session_onstart
+	Session("User") = ""
+	x = Application("ReadOnly")
+End Sub
+Sub Helper
+End Sub
+</script>
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse("global.asa", strings.NewReader(asa))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []struct {
+		name  string
+		found bool
+	}{
+		{ApplicationOnStart, true},
+		{SessionOnStart, true},
+		{"SESSION_ONSTART", true},
+		{ApplicationOnEnd, false},
+		{"Helper", false},
+	}
+	for _, tt := range events {
+		if e := f.Event(tt.name); (e != nil) != tt.found {
+			t.Errorf("Event(%q) = %v, want found %v", tt.name, e, tt.found)
+		}
+	}
+	if len(f.Events) != 2 || f.Events[1].Name != SessionOnStart {
+		t.Errorf("got %d events, want Application_OnStart and Session_OnStart", len(f.Events))
+	}
+
+	objects := []struct {
+		id, scope, progID, classID string
+	}{
+		{"Conn", "Application", "ADODB.Connection", ""},
+		{"cart", "Session", "", "clsid:00000000-0000-0000-0000-000000000000"},
+	}
+	for _, tt := range objects {
+		o := f.Object(tt.id)
+		if o == nil {
+			t.Errorf("Object(%q) not found", tt.id)
+			continue
+		}
+		if o.Scope != tt.scope || o.ProgID != tt.progID || o.ClassID != tt.classID {
+			t.Errorf("Object(%q) = %+v", tt.id, o)
+		}
+	}
+	if o := f.Object("Client"); o != nil {
+		t.Errorf("client-side object found: %+v", o)
+	}
+
+	vars := []struct {
+		vars map[string]*Variable
+		key  string
+		name string
+		line int
+	}{
+		{f.Application, "count", "Count", 6},
+		{f.Application, "name", "Name", 7},
+		{f.Session, "user", "User", 11},
+		{f.Application, "readonly", "", 0},
+	}
+	for _, tt := range vars {
+		v := tt.vars[tt.key]
+		switch {
+		case tt.name == "" && v != nil:
+			t.Errorf("variable %q is set at %v", tt.key, v.Pos)
+		case tt.name != "" && v == nil:
+			t.Errorf("variable %q not found", tt.key)
+		case v != nil && (v.Name != tt.name || v.Pos.Line != tt.line):
+			t.Errorf("variable %q = %s at line %d, want %s at line %d", tt.key, v.Name, v.Pos.Line, tt.name, tt.line)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	src := "<script language=\"VBScript\" runat=\"server\">\nSub Application_OnStart\n\tApplication(\"a\") = \nEnd Sub\n</script>\n"
+	f, err := Parse("global.asa", strings.NewReader(src))
+	if err == nil {
+		t.Fatal("no error")
+	}
+	if f == nil || f.Event(ApplicationOnStart) == nil {
+		t.Errorf("partial file not kept: %v", f)
+	}
+}
@@ -0,0 +1,188 @@
+package vbinclude
+
+import (
+	"iter"
+	"os"
+	"path/filepath"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// Token is a lexer token tagged with the file it was read from. The line
+// within that file is Start.Line.
+type Token struct {
+	vblexer.Token
+	File     string             // file the token was read from
+	Includes Stack              // include directives through which the file was read
+	Expanded vbscanner.Position // start of the token in the expanded page
+}
+
+// Stack returns the include stack of the token, ending with its own location.
+func (t Token) Stack() Stack {
+	return append(append(Stack{}, t.Includes...), Location{File: t.File, Pos: t.Start})
+}
+
+// Lex reads a page and the files it includes as a single token stream.
+// Each include directive is returned, followed by the tokens of the included
+// file, followed by the rest of the including file. Directives that cannot
+// be followed are recorded in Errors and skipped, and problems found while
+// lexing the files are recorded in LexErrors.
+//
+// As it reads, the lexer builds a source map of the expanded page, in which
+// each include directive is replaced by the text of the file it names.
+type Lex struct {
+	Resolver  *Resolver
+	Errors    []*Error           // includes that could not be followed
+	LexErrors []*LexError        // problems found while lexing the files
+	Map       SourceMap          // source map of the expanded page read so far
+	stack     []*frame           // files being read, innermost last
+	exp       vbscanner.Position // current position in the expanded page
+}
+
+// frame is a file being read by the lexer.
+type frame struct {
+	lex      vblexer.Lex
+	file     *os.File
+	name     string
+	includes Stack         // include directives through which the file was read
+	include  vblexer.Token // include directive being followed
+	errs     int           // problems of lex already recorded
+}
+
+// LexError is a problem found while lexing one of the files, with the
+// include directives through which the file was read.
+type LexError struct {
+	*vblexer.Error
+	Includes Stack
+}
+
+// Stack returns the include stack of the problem, ending with its own location.
+func (e *LexError) Stack() Stack {
+	return append(append(Stack{}, e.Includes...), Location{File: e.Filename, Pos: e.Pos})
+}
+
+// Init prepares the lexer to read the page in fname. The resolver locates the
+// included files, which are always read in HTML mode.
+func (lex *Lex) Init(r *Resolver, fname string, initialMode vbscanner.Mode) error {
+	lex.Close()
+	lex.Resolver = r
+	lex.Errors = nil
+	lex.LexErrors = nil
+	lex.Map = SourceMap{}
+	lex.exp = vbscanner.Position{Line: 1, Column: 1}
+	return lex.open(fname, initialMode, nil)
+}
+
+// SetHTA turns HTA mode on or off for the page, for reading an HTML
+// Application. It should be called after Init, before the first token is read.
+func (lex *Lex) SetHTA(on bool) {
+	lex.stack[0].lex.SetHTA(on)
+}
+
+// Stack returns the include stack for pos in the file currently being read,
+// for reporting errors found in it.
+func (lex *Lex) Stack(pos vbscanner.Position) Stack {
+	f := lex.stack[len(lex.stack)-1]
+	return append(append(Stack{}, f.includes...), Location{File: f.name, Pos: pos})
+}
+
+// Close closes any files still open.
+func (lex *Lex) Close() {
+	for _, f := range lex.stack {
+		f.file.Close()
+	}
+	lex.stack = nil
+}
+
+// open starts reading the file in name, which was included through the
+// given directives.
+func (lex *Lex) open(name string, mode vbscanner.Mode, includes Stack) error {
+	fil, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	f := &frame{file: fil, name: name, includes: includes}
+	f.lex.Init(fil, name, mode)
+	f.lex.SetLossless(true)
+	lex.stack = append(lex.stack, f)
+	lex.Map.begin(lex.exp, Location{File: name, Pos: vbscanner.Position{Line: 1, Column: 1}}, includes)
+	return nil
+}
+
+// Next returns the next token in the merged stream. Once the end of the
+// page is reached, it returns EOF tokens.
+func (lex *Lex) Next() Token {
+	for {
+		f := lex.stack[len(lex.stack)-1]
+		t := f.lex.Next()
+		for _, err := range f.lex.Errors()[f.errs:] {
+			lex.LexErrors = append(lex.LexErrors, &LexError{Error: err, Includes: f.includes})
+		}
+		f.errs = len(f.lex.Errors())
+		tok := Token{Token: t, File: f.name, Includes: f.includes, Expanded: lex.exp.Advance(t.Leading)}
+		if (t.Type == vblexer.FILE_INCLUDE || t.Type == vblexer.VIRTUAL_INCLUDE) && lex.include(f, t) {
+			return tok
+		}
+		lex.exp = lex.exp.Advance(t.Leading + t.Text + t.Trailing)
+		lex.Map.extend(lex.exp)
+		if t.Type == vblexer.EOF && len(lex.stack) > 1 {
+			f.file.Close()
+			lex.stack = lex.stack[:len(lex.stack)-1]
+			parent := lex.stack[len(lex.stack)-1]
+			lex.Map.begin(lex.exp, Location{File: parent.name, Pos: parent.include.End}, parent.includes)
+			continue
+		}
+		return tok
+	}
+}
+
+// All returns an iterator over the remaining tokens in the merged stream,
+// stopping at the EOF of the page.
+func (lex *Lex) All() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for {
+			t := lex.Next()
+			if t.Type == vblexer.EOF || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// include follows the include directive t found in f, and reports whether
+// the included file was opened.
+func (lex *Lex) include(f *frame, t vblexer.Token) bool {
+	name := t.Value.(string)
+	fail := func(err error) {
+		lex.Errors = append(lex.Errors, &Error{Filename: f.name, Pos: t.Start, Path: name, Err: err})
+	}
+	p, err := lex.Resolver.Resolve(f.name, t.Type == vblexer.VIRTUAL_INCLUDE, name)
+	if err != nil {
+		fail(err)
+		return false
+	}
+	for _, open := range lex.stack {
+		if sameFile(open.name, p) {
+			fail(ErrCycle)
+			return false
+		}
+	}
+	includes := append(append(Stack{}, f.includes...), Location{File: f.name, Pos: t.Start})
+	if err := lex.open(p, vbscanner.HTML_MODE, includes); err != nil {
+		fail(err)
+		return false
+	}
+	f.include = t
+	return true
+}
+
+// sameFile reports whether a and b name the same file.
+func sameFile(a, b string) bool {
+	if fa, err := os.Stat(a); err == nil {
+		if fb, err := os.Stat(b); err == nil {
+			return os.SameFile(fa, fb)
+		}
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,171 @@
+package vbinclude
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// site writes the files, given by slash-separated path, to a temporary
+// directory and returns it.
+func site(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, text := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestResolve(t *testing.T) {
+	root := site(t, map[string]string{
+		"page.asp":        "",
+		"inc/db.asp":      "",
+		"inc/Util.asp":    "",
+		"shared/lib.asp":  "",
+		"other/lib.asp":   "",
+		"other/x/lib.asp": "",
+	})
+	r := &Resolver{Root: root, Virtual: map[string]string{
+		"/shared":   filepath.Join(root, "other"),
+		"/shared/x": filepath.Join(root, "other", "x"),
+	}}
+	from := filepath.Join(root, "page.asp")
+	tests := []struct {
+		virtual bool
+		name    string
+		want    string // slash-separated path under root, or "" for an error
+	}{
+		{false, "inc/db.asp", "inc/db.asp"},
+		{false, `inc\db.asp`, "inc/db.asp"},
+		{false, "INC/util.ASP", "inc/Util.asp"},
+		{true, "/inc/db.asp", "inc/db.asp"},
+		{true, "inc/db.asp", "inc/db.asp"},
+		{true, "/shared/lib.asp", "other/lib.asp"},
+		{true, "/SHARED/lib.asp", "other/lib.asp"},
+		{true, "/shared/x/lib.asp", "other/x/lib.asp"},
+		{false, "shared/lib.asp", "shared/lib.asp"},
+		{false, "inc/missing.asp", ""},
+	}
+	for _, tt := range tests {
+		got, err := r.Resolve(from, tt.virtual, tt.name)
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("Resolve(%v, %q) = %q, want error", tt.virtual, tt.name, got)
+		case tt.want != "" && err != nil:
+			t.Errorf("Resolve(%v, %q): %v", tt.virtual, tt.name, err)
+		case tt.want != "" && got != filepath.Join(root, filepath.FromSlash(tt.want)):
+			t.Errorf("Resolve(%v, %q) = %q, want %q", tt.virtual, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLex(t *testing.T) {
+	root := site(t, map[string]string{
+		"page.asp":  "<html>\n<!-- #include file=\"a.asp\" -->\n<% x = 1 %>\n<!-- #include file=\"missing.asp\" -->\n</html>\n",
+		"a.asp":     "<% Dim a %>\n<!-- #include virtual=\"/inc/b.asp\" -->\n<% a = 2 %>\n",
+		"inc/b.asp": "<% b = 3 %>\n<!-- #include file=\"../page.asp\" -->\n",
+	})
+	var lex Lex
+	page := filepath.Join(root, "page.asp")
+	if err := lex.Init(&Resolver{Root: root}, page, vbscanner.HTML_MODE); err != nil {
+		t.Fatal(err)
+	}
+	defer lex.Close()
+
+	// identifiers with the file they were read from and their include stack
+	tests := []struct {
+		name  string
+		file  string
+		line  int
+		stack string
+	}{
+		{"a", "a.asp", 1, "page.asp:2 -> a.asp:1"},
+		{"b", "inc/b.asp", 1, "page.asp:2 -> a.asp:2 -> inc/b.asp:1"},
+		{"a", "a.asp", 3, "page.asp:2 -> a.asp:3"},
+		{"x", "page.asp", 3, "page.asp:3"},
+	}
+	var got []Token
+	for tok := range lex.All() {
+		if tok.Type == vblexer.IDENTIFIER {
+			got = append(got, tok)
+		}
+	}
+	if len(got) != len(tests) {
+		t.Fatalf("got %d identifiers, want %d", len(got), len(tests))
+	}
+	rel := func(p string) string {
+		r, _ := filepath.Rel(root, p)
+		return filepath.ToSlash(r)
+	}
+	for i, tt := range tests {
+		tok := got[i]
+		stack := tok.Stack()
+		for j := range stack {
+			stack[j].File = rel(stack[j].File)
+		}
+		if tok.Raw != tt.name || rel(tok.File) != tt.file || tok.Start.Line != tt.line || stack.String() != tt.stack {
+			t.Errorf("identifier %d: got %s in %s:%d via %s, want %s in %s:%d via %s",
+				i, tok.Raw, rel(tok.File), tok.Start.Line, stack, tt.name, tt.file, tt.line, tt.stack)
+		}
+		// the source map leads back to the same place
+		src := lex.Map.Source(tok.Expanded)
+		if len(src) == 0 || src[len(src)-1].File != tok.File || src[len(src)-1].Pos.Line != tok.Start.Line {
+			t.Errorf("identifier %d: source map gives %v", i, src)
+		}
+	}
+
+	// the cycle and the missing file are reported
+	var msgs []string
+	for _, err := range lex.Errors {
+		switch {
+		case errors.Is(err, ErrCycle):
+			msgs = append(msgs, "cycle "+rel(err.Filename))
+		case errors.Is(err, os.ErrNotExist):
+			msgs = append(msgs, "missing "+rel(err.Filename))
+		default:
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if s := strings.Join(msgs, ", "); s != "cycle inc/b.asp, missing page.asp" {
+		t.Errorf("errors: %s", s)
+	}
+}
+
+func TestSetHTA(t *testing.T) {
+	root := site(t, map[string]string{
+		"app.hta": "<% a %>\n<script language=\"VBScript\">b = 1</script>\n",
+	})
+	for _, hta := range []bool{false, true} {
+		var lex Lex
+		if err := lex.Init(&Resolver{Root: root}, filepath.Join(root, "app.hta"), vbscanner.HTML_MODE); err != nil {
+			t.Fatal(err)
+		}
+		lex.SetHTA(hta)
+		var names []string
+		for tok := range lex.All() {
+			if tok.Type == vblexer.IDENTIFIER {
+				names = append(names, tok.Raw)
+			}
+		}
+		lex.Close()
+		want := "a"
+		if hta {
+			want = "b"
+		}
+		if got := strings.Join(names, " "); got != want {
+			t.Errorf("HTA %v: got identifiers %q, want %q", hta, got, want)
+		}
+	}
+}
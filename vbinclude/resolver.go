@@ -0,0 +1,127 @@
+// Package vbinclude resolves the #include directives in ASP pages.
+package vbinclude
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// ErrCycle is reported when a file includes itself, directly or indirectly.
+var ErrCycle = errors.New("include cycle")
+
+// Error describes an #include directive that could not be followed.
+type Error struct {
+	Filename string             // file containing the directive
+	Pos      vbscanner.Position // position of the directive
+	Path     string             // path given in the directive
+	Err      error              // reason the include failed
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%s: include %q: %v", e.Filename, e.Pos, e.Path, e.Err)
+}
+
+// Unwrap returns the reason the include failed.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Resolver finds the files named by #include directives, the way IIS does.
+// file= paths are relative to the including file, and virtual= paths are
+// relative to the web root, or to a virtual directory if the path starts
+// with one.
+type Resolver struct {
+	Root    string            // physical path of the web root
+	Virtual map[string]string // virtual directories, like "/shared", mapped to physical paths
+}
+
+// Resolve returns the physical path of the file named by an #include directive
+// in the file from. If the exact path does not exist, a case-insensitive match
+// is tried, since IIS runs on case-insensitive file systems.
+func (r *Resolver) Resolve(from string, virtual bool, name string) (string, error) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	var p string
+	if virtual && strings.HasPrefix(name, "/") {
+		p = r.virtualPath(path.Clean(name))
+	} else {
+		p = filepath.Join(filepath.Dir(from), filepath.FromSlash(name))
+	}
+	if _, err := os.Stat(p); err == nil {
+		return p, nil
+	} else if found, ok := findFold(p); ok {
+		return found, nil
+	} else {
+		return p, err
+	}
+}
+
+// virtualPath maps a rooted virtual path to a physical path, using the
+// longest matching virtual directory.
+func (r *Resolver) virtualPath(name string) string {
+	dirs := make([]string, 0, len(r.Virtual))
+	for dir := range r.Virtual {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+	for _, dir := range dirs {
+		v := "/" + strings.Trim(strings.ReplaceAll(dir, "\\", "/"), "/")
+		if strings.EqualFold(name, v) || len(name) > len(v) && strings.EqualFold(name[:len(v)+1], v+"/") {
+			return filepath.Join(r.Virtual[dir], filepath.FromSlash(name[len(v):]))
+		}
+	}
+	root := r.Root
+	if root == "" {
+		root = "."
+	}
+	return filepath.Join(root, filepath.FromSlash(name))
+}
+
+// findFold looks for p one path element at a time, ignoring case.
+func findFold(p string) (string, bool) {
+	vol := filepath.VolumeName(p)
+	rest := p[len(vol):]
+	dir := vol
+	if strings.HasPrefix(rest, string(filepath.Separator)) {
+		dir += string(filepath.Separator)
+	} else {
+		dir += "."
+	}
+	for _, elem := range strings.Split(rest, string(filepath.Separator)) {
+		if elem == "" || elem == "." {
+			continue
+		}
+		next := filepath.Join(dir, elem)
+		if elem == ".." {
+			dir = next
+			continue
+		}
+		if _, err := os.Stat(next); err == nil {
+			dir = next
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", false
+		}
+		found := false
+		for _, e := range entries {
+			if strings.EqualFold(e.Name(), elem) {
+				dir = filepath.Join(dir, e.Name())
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", false
+		}
+	}
+	return dir, true
+}
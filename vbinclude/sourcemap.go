@@ -0,0 +1,115 @@
+package vbinclude
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// Location is a position within a particular file.
+type Location struct {
+	File string
+	Pos  vbscanner.Position
+}
+
+// String returns the location as file:line:column.
+func (l Location) String() string {
+	return l.File + ":" + l.Pos.String()
+}
+
+// Stack is a chain of locations through include directives, outermost first.
+// Every location but the last is an #include directive.
+type Stack []Location
+
+// String formats the stack the way IIS reports it, like
+// "page.asp:12 -> inc/db.asp:40".
+func (s Stack) String() string {
+	parts := make([]string, len(s))
+	for i, l := range s {
+		parts[i] = fmt.Sprintf("%s:%d", l.File, l.Pos.Line)
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// Segment is a range of the expanded page copied unchanged from one file.
+type Segment struct {
+	Start    vbscanner.Position // start of the range in the expanded page
+	End      vbscanner.Position // end of the range in the expanded page
+	Src      Location           // where the range starts in its file
+	Includes Stack              // include directives through which the file was read
+}
+
+// contains reports whether the expanded offset is in the segment, counting
+// the end position if inclusive is set.
+func (seg *Segment) contains(offset int, inclusive bool) bool {
+	return seg.Start.Offset <= offset && (offset < seg.End.Offset || inclusive && offset == seg.End.Offset)
+}
+
+// SourceMap relates positions in a page with its includes expanded, the way
+// IIS sees it, to positions in the files it was read from.
+type SourceMap struct {
+	Segments []Segment // in expanded page order
+}
+
+// Source returns the include stack for a position in the expanded page, ending
+// with the location in the file the position came from. It returns nil if the
+// position is outside the page.
+func (m *SourceMap) Source(pos vbscanner.Position) Stack {
+	i := sort.Search(len(m.Segments), func(i int) bool {
+		return m.Segments[i].End.Offset > pos.Offset
+	})
+	if i == len(m.Segments) {
+		i--
+	}
+	if i < 0 || !m.Segments[i].contains(pos.Offset, i == len(m.Segments)-1) {
+		return nil
+	}
+	seg := &m.Segments[i]
+	src := Location{File: seg.Src.File, Pos: translate(pos, seg.Start, seg.Src.Pos)}
+	return append(append(Stack{}, seg.Includes...), src)
+}
+
+// Expanded returns the positions in the expanded page that came from pos in
+// file. A file included more than once has more than one expanded position.
+// A position at the end of an included file maps to the end of its text, even
+// though Source attributes that position to the text following it.
+func (m *SourceMap) Expanded(file string, pos vbscanner.Position) []vbscanner.Position {
+	var res []vbscanner.Position
+	for i := range m.Segments {
+		seg := &m.Segments[i]
+		if seg.Src.File != file {
+			continue
+		}
+		exp := translate(pos, seg.Src.Pos, seg.Start)
+		if seg.contains(exp.Offset, true) {
+			res = append(res, exp)
+		}
+	}
+	return res
+}
+
+// translate maps pos, relative to from, to the same place relative to to.
+// The text following from and to must be the same.
+func translate(pos, from, to vbscanner.Position) vbscanner.Position {
+	res := vbscanner.Position{
+		Offset: to.Offset + pos.Offset - from.Offset,
+		Line:   to.Line + pos.Line - from.Line,
+		Column: pos.Column,
+	}
+	if pos.Line == from.Line {
+		res.Column = to.Column + pos.Column - from.Column
+	}
+	return res
+}
+
+// begin starts a new segment at the current expanded position.
+func (m *SourceMap) begin(exp vbscanner.Position, src Location, includes Stack) {
+	m.Segments = append(m.Segments, Segment{Start: exp, End: exp, Src: src, Includes: includes})
+}
+
+// extend moves the end of the current segment to exp.
+func (m *SourceMap) extend(exp vbscanner.Position) {
+	m.Segments[len(m.Segments)-1].End = exp
+}
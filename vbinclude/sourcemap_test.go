@@ -0,0 +1,62 @@
+package vbinclude
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+func TestExpanded(t *testing.T) {
+	root := site(t, map[string]string{
+		"page.asp": "<% x %>\n<!-- #include file=\"a.asp\" -->\n<!-- #include file=\"a.asp\" -->\n<% y %>",
+		"a.asp":    "<% a %>\n",
+	})
+	var lex Lex
+	if err := lex.Init(&Resolver{Root: root}, filepath.Join(root, "page.asp"), vbscanner.HTML_MODE); err != nil {
+		t.Fatal(err)
+	}
+	defer lex.Close()
+	var idents []Token
+	for tok := range lex.All() {
+		if tok.Type == vblexer.IDENTIFIER {
+			idents = append(idents, tok)
+		}
+	}
+	if len(idents) != 4 {
+		t.Fatalf("got %d identifiers, want 4", len(idents))
+	}
+
+	// a is read twice, and its position in a.asp is in the expanded page twice
+	a := filepath.Join(root, "a.asp")
+	got := lex.Map.Expanded(a, idents[1].Start)
+	want := []vbscanner.Position{idents[1].Expanded, idents[2].Expanded}
+	if !slices.Equal(got, want) {
+		t.Errorf("a: got %v, want %v", got, want)
+	}
+	if idents[1].Expanded.Line != 2 || idents[2].Expanded.Line != 4 {
+		t.Errorf("a expanded at lines %d and %d, want 2 and 4", idents[1].Expanded.Line, idents[2].Expanded.Line)
+	}
+
+	// the page is expanded once, after the included text
+	page := filepath.Join(root, "page.asp")
+	for _, tok := range []Token{idents[0], idents[3]} {
+		got := lex.Map.Expanded(page, tok.Start)
+		if len(got) != 1 || got[0] != tok.Expanded {
+			t.Errorf("%s: got %v, want %v", tok.Raw, got, tok.Expanded)
+		}
+		src := lex.Map.Source(got[0])
+		if len(src) != 1 || src[0].File != page || src[0].Pos != tok.Start {
+			t.Errorf("%s: source of %v is %v", tok.Raw, got[0], src)
+		}
+	}
+	if idents[3].Start.Line != 4 || idents[3].Expanded.Line != 6 {
+		t.Errorf("y at line %d, expanded at line %d, want 4 and 6", idents[3].Start.Line, idents[3].Expanded.Line)
+	}
+
+	if got := lex.Map.Expanded(filepath.Join(root, "other.asp"), idents[1].Start); got != nil {
+		t.Errorf("other file: got %v", got)
+	}
+}
@@ -0,0 +1,257 @@
+package vblexer
+
+// adoConstants are the constants of ADO, as declared in adovbs.inc.
+var adoConstants = []Symbol{
+	{Name: "adOpenUnspecified", Type: ADO_CONSTANT, Doc: "Cursor type: unspecified (-1)"},
+	{Name: "adOpenForwardOnly", Type: ADO_CONSTANT, Doc: "Cursor type: forward-only (0)"},
+	{Name: "adOpenKeyset", Type: ADO_CONSTANT, Doc: "Cursor type: keyset (1)"},
+	{Name: "adOpenDynamic", Type: ADO_CONSTANT, Doc: "Cursor type: dynamic (2)"},
+	{Name: "adOpenStatic", Type: ADO_CONSTANT, Doc: "Cursor type: static (3)"},
+
+	{Name: "adHoldRecords", Type: ADO_CONSTANT, Doc: "Cursor option: can fetch more records without committing changes (&H100)"},
+	{Name: "adMovePrevious", Type: ADO_CONSTANT, Doc: "Cursor option: supports moving backwards (&H200)"},
+	{Name: "adAddNew", Type: ADO_CONSTANT, Doc: "Cursor option: supports AddNew (&H1000400)"},
+	{Name: "adDelete", Type: ADO_CONSTANT, Doc: "Cursor option: supports Delete (&H1000800)"},
+	{Name: "adUpdate", Type: ADO_CONSTANT, Doc: "Cursor option: supports Update (&H1008000)"},
+	{Name: "adBookmark", Type: ADO_CONSTANT, Doc: "Cursor option: supports bookmarks (&H2000)"},
+	{Name: "adApproxPosition", Type: ADO_CONSTANT, Doc: "Cursor option: supports AbsolutePosition and AbsolutePage (&H4000)"},
+	{Name: "adUpdateBatch", Type: ADO_CONSTANT, Doc: "Cursor option: supports UpdateBatch (&H10000)"},
+	{Name: "adResync", Type: ADO_CONSTANT, Doc: "Cursor option: supports Resync (&H20000)"},
+	{Name: "adNotify", Type: ADO_CONSTANT, Doc: "Cursor option: supports notifications (&H40000)"},
+	{Name: "adFind", Type: ADO_CONSTANT, Doc: "Cursor option: supports Find (&H80000)"},
+	{Name: "adSeek", Type: ADO_CONSTANT, Doc: "Cursor option: supports Seek (&H400000)"},
+	{Name: "adIndex", Type: ADO_CONSTANT, Doc: "Cursor option: supports the Index property (&H800000)"},
+
+	{Name: "adLockUnspecified", Type: ADO_CONSTANT, Doc: "Lock type: unspecified (-1)"},
+	{Name: "adLockReadOnly", Type: ADO_CONSTANT, Doc: "Lock type: read-only (1)"},
+	{Name: "adLockPessimistic", Type: ADO_CONSTANT, Doc: "Lock type: pessimistic (2)"},
+	{Name: "adLockOptimistic", Type: ADO_CONSTANT, Doc: "Lock type: optimistic (3)"},
+	{Name: "adLockBatchOptimistic", Type: ADO_CONSTANT, Doc: "Lock type: optimistic batch updates (4)"},
+
+	{Name: "adOptionUnspecified", Type: ADO_CONSTANT, Doc: "Execute option: unspecified (-1)"},
+	{Name: "adAsyncExecute", Type: ADO_CONSTANT, Doc: "Execute option: run asynchronously (&H10)"},
+	{Name: "adAsyncFetch", Type: ADO_CONSTANT, Doc: "Execute option: fetch remaining rows asynchronously (&H20)"},
+	{Name: "adAsyncFetchNonBlocking", Type: ADO_CONSTANT, Doc: "Execute option: fetch asynchronously without blocking (&H40)"},
+	{Name: "adExecuteNoRecords", Type: ADO_CONSTANT, Doc: "Execute option: return no records (&H80)"},
+	{Name: "adExecuteStream", Type: ADO_CONSTANT, Doc: "Execute option: return a stream (&H400)"},
+	{Name: "adExecuteRecord", Type: ADO_CONSTANT, Doc: "Execute option: return a single record (&H800)"},
+
+	{Name: "adConnectUnspecified", Type: ADO_CONSTANT, Doc: "Connect option: synchronous (-1)"},
+	{Name: "adAsyncConnect", Type: ADO_CONSTANT, Doc: "Connect option: asynchronous (&H10)"},
+
+	{Name: "adStateClosed", Type: ADO_CONSTANT, Doc: "Object state: closed (0)"},
+	{Name: "adStateOpen", Type: ADO_CONSTANT, Doc: "Object state: open (1)"},
+	{Name: "adStateConnecting", Type: ADO_CONSTANT, Doc: "Object state: connecting (2)"},
+	{Name: "adStateExecuting", Type: ADO_CONSTANT, Doc: "Object state: executing (4)"},
+	{Name: "adStateFetching", Type: ADO_CONSTANT, Doc: "Object state: fetching rows (8)"},
+
+	{Name: "adUseNone", Type: ADO_CONSTANT, Doc: "Cursor location: no cursor services (1)"},
+	{Name: "adUseServer", Type: ADO_CONSTANT, Doc: "Cursor location: server-side cursor (2)"},
+	{Name: "adUseClient", Type: ADO_CONSTANT, Doc: "Cursor location: client-side cursor (3)"},
+	{Name: "adUseClientBatch", Type: ADO_CONSTANT, Doc: "Cursor location: client-side cursor (3)"},
+
+	{Name: "adEmpty", Type: ADO_CONSTANT, Doc: "Data type: no value (0)"},
+	{Name: "adSmallInt", Type: ADO_CONSTANT, Doc: "Data type: 2-byte signed integer (2)"},
+	{Name: "adInteger", Type: ADO_CONSTANT, Doc: "Data type: 4-byte signed integer (3)"},
+	{Name: "adSingle", Type: ADO_CONSTANT, Doc: "Data type: single precision floating point (4)"},
+	{Name: "adDouble", Type: ADO_CONSTANT, Doc: "Data type: double precision floating point (5)"},
+	{Name: "adCurrency", Type: ADO_CONSTANT, Doc: "Data type: currency (6)"},
+	{Name: "adDate", Type: ADO_CONSTANT, Doc: "Data type: date (7)"},
+	{Name: "adBSTR", Type: ADO_CONSTANT, Doc: "Data type: null-terminated Unicode string (8)"},
+	{Name: "adIDispatch", Type: ADO_CONSTANT, Doc: "Data type: IDispatch pointer (9)"},
+	{Name: "adError", Type: ADO_CONSTANT, Doc: "Data type: 32-bit error code (10)"},
+	{Name: "adBoolean", Type: ADO_CONSTANT, Doc: "Data type: Boolean (11)"},
+	{Name: "adVariant", Type: ADO_CONSTANT, Doc: "Data type: Variant (12)"},
+	{Name: "adIUnknown", Type: ADO_CONSTANT, Doc: "Data type: IUnknown pointer (13)"},
+	{Name: "adDecimal", Type: ADO_CONSTANT, Doc: "Data type: exact decimal number (14)"},
+	{Name: "adTinyInt", Type: ADO_CONSTANT, Doc: "Data type: 1-byte signed integer (16)"},
+	{Name: "adUnsignedTinyInt", Type: ADO_CONSTANT, Doc: "Data type: 1-byte unsigned integer (17)"},
+	{Name: "adUnsignedSmallInt", Type: ADO_CONSTANT, Doc: "Data type: 2-byte unsigned integer (18)"},
+	{Name: "adUnsignedInt", Type: ADO_CONSTANT, Doc: "Data type: 4-byte unsigned integer (19)"},
+	{Name: "adBigInt", Type: ADO_CONSTANT, Doc: "Data type: 8-byte signed integer (20)"},
+	{Name: "adUnsignedBigInt", Type: ADO_CONSTANT, Doc: "Data type: 8-byte unsigned integer (21)"},
+	{Name: "adFileTime", Type: ADO_CONSTANT, Doc: "Data type: file time (64)"},
+	{Name: "adGUID", Type: ADO_CONSTANT, Doc: "Data type: GUID (72)"},
+	{Name: "adBinary", Type: ADO_CONSTANT, Doc: "Data type: binary (128)"},
+	{Name: "adChar", Type: ADO_CONSTANT, Doc: "Data type: string (129)"},
+	{Name: "adWChar", Type: ADO_CONSTANT, Doc: "Data type: null-terminated Unicode string (130)"},
+	{Name: "adNumeric", Type: ADO_CONSTANT, Doc: "Data type: exact numeric value (131)"},
+	{Name: "adUserDefined", Type: ADO_CONSTANT, Doc: "Data type: user-defined (132)"},
+	{Name: "adDBDate", Type: ADO_CONSTANT, Doc: "Data type: date (yyyymmdd) (133)"},
+	{Name: "adDBTime", Type: ADO_CONSTANT, Doc: "Data type: time (hhmmss) (134)"},
+	{Name: "adDBTimeStamp", Type: ADO_CONSTANT, Doc: "Data type: date and time stamp (135)"},
+	{Name: "adChapter", Type: ADO_CONSTANT, Doc: "Data type: chapter (136)"},
+	{Name: "adPropVariant", Type: ADO_CONSTANT, Doc: "Data type: PROPVARIANT (138)"},
+	{Name: "adVarNumeric", Type: ADO_CONSTANT, Doc: "Data type: variable length numeric value (139)"},
+	{Name: "adVarChar", Type: ADO_CONSTANT, Doc: "Data type: variable length string (200)"},
+	{Name: "adLongVarChar", Type: ADO_CONSTANT, Doc: "Data type: long string (201)"},
+	{Name: "adVarWChar", Type: ADO_CONSTANT, Doc: "Data type: variable length Unicode string (202)"},
+	{Name: "adLongVarWChar", Type: ADO_CONSTANT, Doc: "Data type: long Unicode string (203)"},
+	{Name: "adVarBinary", Type: ADO_CONSTANT, Doc: "Data type: variable length binary (204)"},
+	{Name: "adLongVarBinary", Type: ADO_CONSTANT, Doc: "Data type: long binary (205)"},
+	{Name: "adArray", Type: ADO_CONSTANT, Doc: "Data type: flag for an array of another type (&H2000)"},
+
+	{Name: "adFldMayDefer", Type: ADO_CONSTANT, Doc: "Field attribute: deferred (2)"},
+	{Name: "adFldUpdatable", Type: ADO_CONSTANT, Doc: "Field attribute: writable (4)"},
+	{Name: "adFldUnknownUpdatable", Type: ADO_CONSTANT, Doc: "Field attribute: may be writable (8)"},
+	{Name: "adFldFixed", Type: ADO_CONSTANT, Doc: "Field attribute: fixed length (&H10)"},
+	{Name: "adFldIsNullable", Type: ADO_CONSTANT, Doc: "Field attribute: accepts Null (&H20)"},
+	{Name: "adFldMayBeNull", Type: ADO_CONSTANT, Doc: "Field attribute: may hold Null (&H40)"},
+	{Name: "adFldLong", Type: ADO_CONSTANT, Doc: "Field attribute: long binary (&H80)"},
+	{Name: "adFldRowID", Type: ADO_CONSTANT, Doc: "Field attribute: row ID (&H100)"},
+	{Name: "adFldRowVersion", Type: ADO_CONSTANT, Doc: "Field attribute: row version (&H200)"},
+	{Name: "adFldCacheDeferred", Type: ADO_CONSTANT, Doc: "Field attribute: cached (&H1000)"},
+	{Name: "adFldKeyColumn", Type: ADO_CONSTANT, Doc: "Field attribute: part of the key (&H8000)"},
+
+	{Name: "adEditNone", Type: ADO_CONSTANT, Doc: "Edit mode: no edit (0)"},
+	{Name: "adEditInProgress", Type: ADO_CONSTANT, Doc: "Edit mode: changed but not saved (1)"},
+	{Name: "adEditAdd", Type: ADO_CONSTANT, Doc: "Edit mode: added with AddNew (2)"},
+	{Name: "adEditDelete", Type: ADO_CONSTANT, Doc: "Edit mode: deleted (4)"},
+
+	{Name: "adRecOK", Type: ADO_CONSTANT, Doc: "Record status: updated (0)"},
+	{Name: "adRecNew", Type: ADO_CONSTANT, Doc: "Record status: new (1)"},
+	{Name: "adRecModified", Type: ADO_CONSTANT, Doc: "Record status: modified (2)"},
+	{Name: "adRecDeleted", Type: ADO_CONSTANT, Doc: "Record status: deleted (4)"},
+	{Name: "adRecUnmodified", Type: ADO_CONSTANT, Doc: "Record status: unmodified (8)"},
+
+	{Name: "adGetRowsRest", Type: ADO_CONSTANT, Doc: "GetRows option: the rest of the rows (-1)"},
+
+	{Name: "adPosUnknown", Type: ADO_CONSTANT, Doc: "Position: unknown (-1)"},
+	{Name: "adPosBOF", Type: ADO_CONSTANT, Doc: "Position: before the first record (-2)"},
+	{Name: "adPosEOF", Type: ADO_CONSTANT, Doc: "Position: after the last record (-3)"},
+
+	{Name: "adBookmarkCurrent", Type: ADO_CONSTANT, Doc: "Bookmark: current record (0)"},
+	{Name: "adBookmarkFirst", Type: ADO_CONSTANT, Doc: "Bookmark: first record (1)"},
+	{Name: "adBookmarkLast", Type: ADO_CONSTANT, Doc: "Bookmark: last record (2)"},
+
+	{Name: "adMarshalAll", Type: ADO_CONSTANT, Doc: "Marshal option: all rows (0)"},
+	{Name: "adMarshalModifiedOnly", Type: ADO_CONSTANT, Doc: "Marshal option: modified rows (1)"},
+
+	{Name: "adAffectCurrent", Type: ADO_CONSTANT, Doc: "Affect: current record (1)"},
+	{Name: "adAffectGroup", Type: ADO_CONSTANT, Doc: "Affect: records matching the filter (2)"},
+	{Name: "adAffectAll", Type: ADO_CONSTANT, Doc: "Affect: all records (3)"},
+	{Name: "adAffectAllChapters", Type: ADO_CONSTANT, Doc: "Affect: all chapters (4)"},
+
+	{Name: "adResyncUnderlyingValues", Type: ADO_CONSTANT, Doc: "Resync: underlying values (1)"},
+	{Name: "adResyncAllValues", Type: ADO_CONSTANT, Doc: "Resync: all values (2)"},
+
+	{Name: "adCompareLessThan", Type: ADO_CONSTANT, Doc: "Compare: less than (0)"},
+	{Name: "adCompareEqual", Type: ADO_CONSTANT, Doc: "Compare: equal (1)"},
+	{Name: "adCompareGreaterThan", Type: ADO_CONSTANT, Doc: "Compare: greater than (2)"},
+	{Name: "adCompareNotEqual", Type: ADO_CONSTANT, Doc: "Compare: not equal (3)"},
+	{Name: "adCompareNotComparable", Type: ADO_CONSTANT, Doc: "Compare: not comparable (4)"},
+
+	{Name: "adFilterNone", Type: ADO_CONSTANT, Doc: "Filter group: no filter (0)"},
+	{Name: "adFilterPendingRecords", Type: ADO_CONSTANT, Doc: "Filter group: changed records not yet sent (1)"},
+	{Name: "adFilterAffectedRecords", Type: ADO_CONSTANT, Doc: "Filter group: records affected by the last operation (2)"},
+	{Name: "adFilterFetchedRecords", Type: ADO_CONSTANT, Doc: "Filter group: records in the cache (3)"},
+	{Name: "adFilterConflictingRecords", Type: ADO_CONSTANT, Doc: "Filter group: records that failed the last batch update (5)"},
+
+	{Name: "adSearchForward", Type: ADO_CONSTANT, Doc: "Search direction: forward (1)"},
+	{Name: "adSearchBackward", Type: ADO_CONSTANT, Doc: "Search direction: backward (-1)"},
+
+	{Name: "adPersistADTG", Type: ADO_CONSTANT, Doc: "Persist format: Advanced Data Tablegram (0)"},
+	{Name: "adPersistXML", Type: ADO_CONSTANT, Doc: "Persist format: XML (1)"},
+
+	{Name: "adClipString", Type: ADO_CONSTANT, Doc: "String format: delimited rows (2)"},
+
+	{Name: "adPromptAlways", Type: ADO_CONSTANT, Doc: "Connect prompt: always (1)"},
+	{Name: "adPromptComplete", Type: ADO_CONSTANT, Doc: "Connect prompt: if more information is needed (2)"},
+	{Name: "adPromptCompleteRequired", Type: ADO_CONSTANT, Doc: "Connect prompt: if more information is needed, with optional fields disabled (3)"},
+	{Name: "adPromptNever", Type: ADO_CONSTANT, Doc: "Connect prompt: never (4)"},
+
+	{Name: "adModeUnknown", Type: ADO_CONSTANT, Doc: "Connect mode: unknown (0)"},
+	{Name: "adModeRead", Type: ADO_CONSTANT, Doc: "Connect mode: read-only (1)"},
+	{Name: "adModeWrite", Type: ADO_CONSTANT, Doc: "Connect mode: write-only (2)"},
+	{Name: "adModeReadWrite", Type: ADO_CONSTANT, Doc: "Connect mode: read and write (3)"},
+	{Name: "adModeShareDenyRead", Type: ADO_CONSTANT, Doc: "Connect mode: deny others read access (4)"},
+	{Name: "adModeShareDenyWrite", Type: ADO_CONSTANT, Doc: "Connect mode: deny others write access (8)"},
+	{Name: "adModeShareExclusive", Type: ADO_CONSTANT, Doc: "Connect mode: deny others any access (&HC)"},
+	{Name: "adModeShareDenyNone", Type: ADO_CONSTANT, Doc: "Connect mode: deny others no access (&H10)"},
+	{Name: "adModeRecursive", Type: ADO_CONSTANT, Doc: "Connect mode: apply to child records (&H400000)"},
+
+	{Name: "adXactUnspecified", Type: ADO_CONSTANT, Doc: "Isolation level: unspecified (&HFFFFFFFF)"},
+	{Name: "adXactChaos", Type: ADO_CONSTANT, Doc: "Isolation level: chaos (&H10)"},
+	{Name: "adXactReadUncommitted", Type: ADO_CONSTANT, Doc: "Isolation level: read uncommitted (&H100)"},
+	{Name: "adXactBrowse", Type: ADO_CONSTANT, Doc: "Isolation level: browse (&H100)"},
+	{Name: "adXactCursorStability", Type: ADO_CONSTANT, Doc: "Isolation level: cursor stability (&H1000)"},
+	{Name: "adXactReadCommitted", Type: ADO_CONSTANT, Doc: "Isolation level: read committed (&H1000)"},
+	{Name: "adXactRepeatableRead", Type: ADO_CONSTANT, Doc: "Isolation level: repeatable read (&H10000)"},
+	{Name: "adXactSerializable", Type: ADO_CONSTANT, Doc: "Isolation level: serializable (&H100000)"},
+	{Name: "adXactIsolated", Type: ADO_CONSTANT, Doc: "Isolation level: isolated (&H100000)"},
+
+	{Name: "adXactCommitRetaining", Type: ADO_CONSTANT, Doc: "Transaction attribute: start a new transaction after commit (&H20000)"},
+	{Name: "adXactAbortRetaining", Type: ADO_CONSTANT, Doc: "Transaction attribute: start a new transaction after abort (&H40000)"},
+
+	{Name: "adCmdUnspecified", Type: ADO_CONSTANT, Doc: "Command type: unspecified (-1)"},
+	{Name: "adCmdText", Type: ADO_CONSTANT, Doc: "Command type: SQL text (1)"},
+	{Name: "adCmdTable", Type: ADO_CONSTANT, Doc: "Command type: table name (2)"},
+	{Name: "adCmdStoredProc", Type: ADO_CONSTANT, Doc: "Command type: stored procedure (4)"},
+	{Name: "adCmdUnknown", Type: ADO_CONSTANT, Doc: "Command type: unknown (8)"},
+	{Name: "adCmdFile", Type: ADO_CONSTANT, Doc: "Command type: saved recordset file (&H100)"},
+	{Name: "adCmdTableDirect", Type: ADO_CONSTANT, Doc: "Command type: table name, with all columns (&H200)"},
+
+	{Name: "adParamSigned", Type: ADO_CONSTANT, Doc: "Parameter attribute: signed (&H10)"},
+	{Name: "adParamNullable", Type: ADO_CONSTANT, Doc: "Parameter attribute: accepts Null (&H40)"},
+	{Name: "adParamLong", Type: ADO_CONSTANT, Doc: "Parameter attribute: long binary (&H80)"},
+
+	{Name: "adParamUnknown", Type: ADO_CONSTANT, Doc: "Parameter direction: unknown (0)"},
+	{Name: "adParamInput", Type: ADO_CONSTANT, Doc: "Parameter direction: input (1)"},
+	{Name: "adParamOutput", Type: ADO_CONSTANT, Doc: "Parameter direction: output (2)"},
+	{Name: "adParamInputOutput", Type: ADO_CONSTANT, Doc: "Parameter direction: input and output (3)"},
+	{Name: "adParamReturnValue", Type: ADO_CONSTANT, Doc: "Parameter direction: return value (4)"},
+
+	{Name: "adStatusOK", Type: ADO_CONSTANT, Doc: "Event status: succeeded (1)"},
+	{Name: "adStatusErrorsOccurred", Type: ADO_CONSTANT, Doc: "Event status: failed (2)"},
+	{Name: "adStatusCantDeny", Type: ADO_CONSTANT, Doc: "Event status: cannot be cancelled (3)"},
+	{Name: "adStatusCancel", Type: ADO_CONSTANT, Doc: "Event status: cancel the operation (4)"},
+	{Name: "adStatusUnwantedEvent", Type: ADO_CONSTANT, Doc: "Event status: stop sending the event (5)"},
+
+	{Name: "adSchemaCatalogs", Type: ADO_CONSTANT, Doc: "Schema: catalogs (1)"},
+	{Name: "adSchemaColumns", Type: ADO_CONSTANT, Doc: "Schema: columns (4)"},
+	{Name: "adSchemaIndexes", Type: ADO_CONSTANT, Doc: "Schema: indexes (12)"},
+	{Name: "adSchemaProcedures", Type: ADO_CONSTANT, Doc: "Schema: procedures (16)"},
+	{Name: "adSchemaSchemata", Type: ADO_CONSTANT, Doc: "Schema: schemas (17)"},
+	{Name: "adSchemaTables", Type: ADO_CONSTANT, Doc: "Schema: tables (20)"},
+	{Name: "adSchemaProviderTypes", Type: ADO_CONSTANT, Doc: "Schema: data types (22)"},
+	{Name: "adSchemaViews", Type: ADO_CONSTANT, Doc: "Schema: views (23)"},
+	{Name: "adSchemaForeignKeys", Type: ADO_CONSTANT, Doc: "Schema: foreign keys (27)"},
+	{Name: "adSchemaPrimaryKeys", Type: ADO_CONSTANT, Doc: "Schema: primary keys (28)"},
+
+	{Name: "adTypeBinary", Type: ADO_CONSTANT, Doc: "Stream type: binary (1)"},
+	{Name: "adTypeText", Type: ADO_CONSTANT, Doc: "Stream type: text (2)"},
+
+	{Name: "adCRLF", Type: ADO_CONSTANT, Doc: "Line separator: carriage return and line feed (-1)"},
+	{Name: "adLF", Type: ADO_CONSTANT, Doc: "Line separator: line feed (10)"},
+	{Name: "adCR", Type: ADO_CONSTANT, Doc: "Line separator: carriage return (13)"},
+
+	{Name: "adReadAll", Type: ADO_CONSTANT, Doc: "Stream read: read to the end (-1)"},
+	{Name: "adReadLine", Type: ADO_CONSTANT, Doc: "Stream read: read a line (-2)"},
+
+	{Name: "adWriteChar", Type: ADO_CONSTANT, Doc: "Stream write: write the string only (0)"},
+	{Name: "adWriteLine", Type: ADO_CONSTANT, Doc: "Stream write: write the string and a line separator (1)"},
+
+	{Name: "adSaveCreateNotExist", Type: ADO_CONSTANT, Doc: "Save option: create the file if it does not exist (1)"},
+	{Name: "adSaveCreateOverWrite", Type: ADO_CONSTANT, Doc: "Save option: overwrite an existing file (2)"},
+
+	{Name: "adErrNoCurrentRecord", Type: ADO_CONSTANT, Doc: "Error: no current record (&HBCD)"},
+	{Name: "adErrItemNotFound", Type: ADO_CONSTANT, Doc: "Error: item not found in the collection (&HCC1)"},
+	{Name: "adErrObjectClosed", Type: ADO_CONSTANT, Doc: "Error: operation not allowed on a closed object (&HE78)"},
+}
+
+// fsoConstants are the constants used with the FileSystemObject of the
+// Scripting Runtime, which pages often declare themselves.
+var fsoConstants = []Symbol{
+	{Name: "ForReading", Type: FSO_CONSTANT, Doc: "I/O mode: open a file for reading (1)"},
+	{Name: "ForWriting", Type: FSO_CONSTANT, Doc: "I/O mode: open a file for writing (2)"},
+	{Name: "ForAppending", Type: FSO_CONSTANT, Doc: "I/O mode: open a file for appending (8)"},
+
+	{Name: "TristateUseDefault", Type: FSO_CONSTANT, Doc: "Tristate: use the system default (-2)"},
+	{Name: "TristateTrue", Type: FSO_CONSTANT, Doc: "Tristate: Unicode (-1)"},
+	{Name: "TristateFalse", Type: FSO_CONSTANT, Doc: "Tristate: ASCII (0)"},
+	{Name: "TristateMixed", Type: FSO_CONSTANT, Doc: "Tristate: use the system default (-2)"},
+
+	{Name: "WindowsFolder", Type: FSO_CONSTANT, Doc: "Special folder: the Windows folder (0)"},
+	{Name: "SystemFolder", Type: FSO_CONSTANT, Doc: "Special folder: the System folder (1)"},
+	{Name: "TemporaryFolder", Type: FSO_CONSTANT, Doc: "Special folder: the folder for temporary files (2)"},
+}
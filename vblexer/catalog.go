@@ -0,0 +1,325 @@
+package vblexer
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Symbol describes a name the lexer recognizes, like a statement keyword,
+// builtin function or constant. Members of objects are named Object.Member,
+// like Response.Write, and are recognized after a FIELD_SEP that follows the
+// object, or as a whole in dotted identifier mode.
+type Symbol struct {
+	Name    string    // canonical spelling, like InStr or vbCrLf
+	Type    TokenType // token type of the name, like STATEMENT, FUNCTION or COLOR_CONSTANT
+	MinArgs int       // least number of arguments, for functions
+	MaxArgs int       // most number of arguments, for functions, or -1 if there is no limit
+	Doc     string    // short summary
+}
+
+// catalog holds the known symbols by lower case name.
+var catalog = struct {
+	sync.RWMutex
+	symbols map[string]Symbol
+}{symbols: make(map[string]Symbol)}
+
+func init() {
+	Register(statements...)
+	Register(functions...)
+	Register(keywords...)
+	Register(constants...)
+	Register(intrinsics...)
+	Register(intrinsicMembers...)
+	Register(adoConstants...)
+	Register(fsoConstants...)
+}
+
+// Register adds symbols to the catalog used by all lexers, so that their
+// names are lexed as the given token types. Names are not case sensitive. A
+// symbol replaces any registered earlier with the same name, including the
+// builtin ones. Symbols should be registered before lexing starts.
+func Register(syms ...Symbol) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	for _, sym := range syms {
+		catalog.symbols[strings.ToLower(sym.Name)] = sym
+	}
+}
+
+// Lookup returns the symbol with the given name, ignoring case.
+func Lookup(name string) (Symbol, bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	sym, ok := catalog.symbols[strings.ToLower(name)]
+	return sym, ok
+}
+
+// Symbols returns all of the symbols in the catalog, sorted by name.
+func Symbols() []Symbol {
+	catalog.RLock()
+	syms := make([]Symbol, 0, len(catalog.symbols))
+	for _, sym := range catalog.symbols {
+		syms = append(syms, sym)
+	}
+	catalog.RUnlock()
+	slices.SortFunc(syms, func(a, b Symbol) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return syms
+}
+
+// statements are the reserved words of the language.
+var statements = []Symbol{
+	{Name: "ByRef", Type: STATEMENT, Doc: "Passes an argument by reference"},
+	{Name: "ByVal", Type: STATEMENT, Doc: "Passes an argument by value"},
+	{Name: "Call", Type: STATEMENT, Doc: "Calls a Sub or Function procedure"},
+	{Name: "Case", Type: STATEMENT, Doc: "Starts a branch of a Select Case block"},
+	{Name: "Class", Type: STATEMENT, Doc: "Declares a class"},
+	{Name: "Const", Type: STATEMENT, Doc: "Declares constants"},
+	{Name: "Dim", Type: STATEMENT, Doc: "Declares variables"},
+	{Name: "Do", Type: STATEMENT, Doc: "Starts a Do...Loop block"},
+	{Name: "Each", Type: STATEMENT, Doc: "Loops over a collection in For Each"},
+	{Name: "Else", Type: STATEMENT, Doc: "Starts the final branch of an If block"},
+	{Name: "ElseIf", Type: STATEMENT, Doc: "Starts another conditional branch of an If block"},
+	{Name: "End", Type: STATEMENT, Doc: "Ends a block, like End If"},
+	{Name: "Erase", Type: STATEMENT, Doc: "Clears the elements of an array"},
+	{Name: "Error", Type: STATEMENT, Doc: "Part of On Error"},
+	{Name: "Execute", Type: STATEMENT, Doc: "Runs statements in the local scope"},
+	{Name: "ExecuteGlobal", Type: STATEMENT, Doc: "Runs statements in the global scope"},
+	{Name: "Exit", Type: STATEMENT, Doc: "Leaves a loop or procedure"},
+	{Name: "Explicit", Type: STATEMENT, Doc: "Part of Option Explicit"},
+	{Name: "For", Type: STATEMENT, Doc: "Starts a For...Next or For Each...Next loop"},
+	{Name: "Function", Type: STATEMENT, Doc: "Declares a procedure that returns a value"},
+	{Name: "Get", Type: STATEMENT, Doc: "Declares a Property Get procedure"},
+	{Name: "GoTo", Type: STATEMENT, Doc: "Part of On Error GoTo 0"},
+	{Name: "If", Type: STATEMENT, Doc: "Runs statements depending on a condition"},
+	{Name: "In", Type: STATEMENT, Doc: "Names the collection in For Each"},
+	{Name: "Is", Type: STATEMENT, Doc: "Compares object references"},
+	{Name: "Let", Type: STATEMENT, Doc: "Declares a Property Let procedure"},
+	{Name: "Loop", Type: STATEMENT, Doc: "Ends a Do...Loop block"},
+	{Name: "New", Type: STATEMENT, Doc: "Creates an instance of a class"},
+	{Name: "Next", Type: STATEMENT, Doc: "Ends a For loop, or part of On Error Resume Next"},
+	{Name: "On", Type: STATEMENT, Doc: "Part of On Error"},
+	{Name: "Option", Type: STATEMENT, Doc: "Part of Option Explicit"},
+	{Name: "Private", Type: STATEMENT, Doc: "Declares private variables or procedures"},
+	{Name: "Property", Type: STATEMENT, Doc: "Declares a property procedure"},
+	{Name: "Public", Type: STATEMENT, Doc: "Declares public variables or procedures"},
+	{Name: "Raise", Type: STATEMENT, Doc: "Raises an error with Err.Raise"},
+	{Name: "Randomize", Type: STATEMENT, Doc: "Seeds the random number generator"},
+	{Name: "ReDim", Type: STATEMENT, Doc: "Changes the size of a dynamic array"},
+	{Name: "Rem", Type: STATEMENT, Doc: "Starts a comment"},
+	{Name: "Resume", Type: STATEMENT, Doc: "Part of On Error Resume Next"},
+	{Name: "Select", Type: STATEMENT, Doc: "Starts a Select Case block"},
+	{Name: "Set", Type: STATEMENT, Doc: "Assigns an object reference"},
+	{Name: "Step", Type: STATEMENT, Doc: "Gives the increment of a For loop"},
+	{Name: "Stop", Type: STATEMENT, Doc: "Stops for the debugger"},
+	{Name: "Sub", Type: STATEMENT, Doc: "Declares a procedure that does not return a value"},
+	{Name: "Then", Type: STATEMENT, Doc: "Follows the condition of an If"},
+	{Name: "To", Type: STATEMENT, Doc: "Gives the end value of a For loop"},
+	{Name: "WEnd", Type: STATEMENT, Doc: "Ends a While...Wend loop"},
+	{Name: "While", Type: STATEMENT, Doc: "Starts a While...Wend loop, or a condition of a Do loop"},
+	{Name: "With", Type: STATEMENT, Doc: "Runs statements on a single object"},
+}
+
+// functions are the builtin functions.
+var functions = []Symbol{
+	{Name: "Abs", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the absolute value of a number"},
+	{Name: "Array", Type: FUNCTION, MinArgs: 0, MaxArgs: -1, Doc: "Returns an array holding the arguments"},
+	{Name: "Asc", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the character code of the first letter of a string"},
+	{Name: "Atn", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the arctangent of a number"},
+	{Name: "CBool", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a value to a Boolean"},
+	{Name: "CByte", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a value to a Byte"},
+	{Name: "CCur", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a value to a Currency"},
+	{Name: "CDate", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a value to a Date"},
+	{Name: "CDbl", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a value to a Double"},
+	{Name: "Chr", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the character with a character code"},
+	{Name: "CInt", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a value to an Integer"},
+	{Name: "CLng", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a value to a Long"},
+	{Name: "Conversions", Type: FUNCTION, MinArgs: 0, MaxArgs: -1, Doc: "Not a function, but the title of a documentation topic"},
+	{Name: "Cos", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the cosine of an angle"},
+	{Name: "CreateObject", Type: FUNCTION, MinArgs: 1, MaxArgs: 2, Doc: "Creates a COM object"},
+	{Name: "CSng", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a value to a Single"},
+	{Name: "CStr", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a value to a String"},
+	{Name: "Date", Type: FUNCTION, MinArgs: 0, MaxArgs: 0, Doc: "Returns the current date"},
+	{Name: "DateAdd", Type: FUNCTION, MinArgs: 3, MaxArgs: 3, Doc: "Adds an interval to a date"},
+	{Name: "DateDiff", Type: FUNCTION, MinArgs: 3, MaxArgs: 5, Doc: "Returns the number of intervals between two dates"},
+	{Name: "DatePart", Type: FUNCTION, MinArgs: 2, MaxArgs: 4, Doc: "Returns a part of a date, like the quarter"},
+	{Name: "DateSerial", Type: FUNCTION, MinArgs: 3, MaxArgs: 3, Doc: "Returns the date for a year, month and day"},
+	{Name: "DateValue", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a string to a date"},
+	{Name: "Day", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the day of the month of a date"},
+	{Name: "Escape", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Encodes a string with %xx escapes"},
+	{Name: "Eval", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Evaluates an expression in a string"},
+	{Name: "Exp", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns e raised to a power"},
+	{Name: "Filter", Type: FUNCTION, MinArgs: 2, MaxArgs: 4, Doc: "Returns the strings in an array that contain a value"},
+	{Name: "Fix", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the integer part of a number, rounding toward zero"},
+	{Name: "FormatCurrency", Type: FUNCTION, MinArgs: 1, MaxArgs: 5, Doc: "Formats a number as currency"},
+	{Name: "FormatDateTime", Type: FUNCTION, MinArgs: 1, MaxArgs: 2, Doc: "Formats a date or time"},
+	{Name: "FormatNumber", Type: FUNCTION, MinArgs: 1, MaxArgs: 5, Doc: "Formats a number"},
+	{Name: "FormatPercent", Type: FUNCTION, MinArgs: 1, MaxArgs: 5, Doc: "Formats a number as a percentage"},
+	{Name: "GetLocale", Type: FUNCTION, MinArgs: 0, MaxArgs: 0, Doc: "Returns the current locale ID"},
+	{Name: "GetObject", Type: FUNCTION, MinArgs: 0, MaxArgs: 2, Doc: "Returns an object from a file or a running application"},
+	{Name: "GetRef", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns a reference to a procedure"},
+	{Name: "Hex", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns a number in hexadecimal"},
+	{Name: "Hour", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the hour of a time"},
+	{Name: "InputBox", Type: FUNCTION, MinArgs: 1, MaxArgs: 7, Doc: "Asks the user for a string"},
+	{Name: "InStr", Type: FUNCTION, MinArgs: 2, MaxArgs: 4, Doc: "Returns the position of a string within another"},
+	{Name: "InStrRev", Type: FUNCTION, MinArgs: 2, MaxArgs: 4, Doc: "Returns the position of a string within another, searching from the end"},
+	{Name: "Int", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the integer part of a number, rounding down"},
+	{Name: "IsArray", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns whether a value is an array"},
+	{Name: "IsDate", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns whether a value can be converted to a date"},
+	{Name: "IsEmpty", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns whether a variable is uninitialized"},
+	{Name: "IsNull", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns whether a value is Null"},
+	{Name: "IsNumeric", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns whether a value can be converted to a number"},
+	{Name: "IsObject", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns whether a value is an object reference"},
+	{Name: "Join", Type: FUNCTION, MinArgs: 1, MaxArgs: 2, Doc: "Joins the strings in an array"},
+	{Name: "LBound", Type: FUNCTION, MinArgs: 1, MaxArgs: 2, Doc: "Returns the smallest index of an array"},
+	{Name: "LCase", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a string to lower case"},
+	{Name: "Left", Type: FUNCTION, MinArgs: 2, MaxArgs: 2, Doc: "Returns characters from the start of a string"},
+	{Name: "Len", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the length of a string"},
+	{Name: "LoadPicture", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Loads a picture object from a file"},
+	{Name: "Log", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the natural logarithm of a number"},
+	{Name: "LTrim", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Removes leading spaces from a string"},
+	{Name: "MathS", Type: FUNCTION, MinArgs: 0, MaxArgs: -1, Doc: "Not a function, but the title of a documentation topic"},
+	{Name: "Mid", Type: FUNCTION, MinArgs: 2, MaxArgs: 3, Doc: "Returns characters from the middle of a string"},
+	{Name: "Minute", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the minute of a time"},
+	{Name: "Month", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the month of a date"},
+	{Name: "MonthName", Type: FUNCTION, MinArgs: 1, MaxArgs: 2, Doc: "Returns the name of a month"},
+	{Name: "MsgBox", Type: FUNCTION, MinArgs: 1, MaxArgs: 5, Doc: "Shows a message and returns the button clicked"},
+	{Name: "Now", Type: FUNCTION, MinArgs: 0, MaxArgs: 0, Doc: "Returns the current date and time"},
+	{Name: "Oct", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns a number in octal"},
+	{Name: "Replace", Type: FUNCTION, MinArgs: 3, MaxArgs: 6, Doc: "Replaces occurrences of a string within another"},
+	{Name: "Rgb", Type: FUNCTION, MinArgs: 3, MaxArgs: 3, Doc: "Returns a color from red, green and blue values"},
+	{Name: "Right", Type: FUNCTION, MinArgs: 2, MaxArgs: 2, Doc: "Returns characters from the end of a string"},
+	{Name: "Rnd", Type: FUNCTION, MinArgs: 0, MaxArgs: 1, Doc: "Returns a random number"},
+	{Name: "Round", Type: FUNCTION, MinArgs: 1, MaxArgs: 2, Doc: "Rounds a number"},
+	{Name: "RTrim", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Removes trailing spaces from a string"},
+	{Name: "ScriptEngine", Type: FUNCTION, MinArgs: 0, MaxArgs: 0, Doc: "Returns the name of the scripting language"},
+	{Name: "ScriptEngineBuildVersion", Type: FUNCTION, MinArgs: 0, MaxArgs: 0, Doc: "Returns the build number of the script engine"},
+	{Name: "ScriptEngineMajorVersion", Type: FUNCTION, MinArgs: 0, MaxArgs: 0, Doc: "Returns the major version of the script engine"},
+	{Name: "ScriptEngineMinorVersion", Type: FUNCTION, MinArgs: 0, MaxArgs: 0, Doc: "Returns the minor version of the script engine"},
+	{Name: "Second", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the second of a time"},
+	{Name: "SetLocale", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Sets the locale and returns the previous one"},
+	{Name: "Sgn", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the sign of a number"},
+	{Name: "Sin", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the sine of an angle"},
+	{Name: "Space", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns a string of spaces"},
+	{Name: "Split", Type: FUNCTION, MinArgs: 1, MaxArgs: 4, Doc: "Splits a string into an array"},
+	{Name: "Sqr", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the square root of a number"},
+	{Name: "StrComp", Type: FUNCTION, MinArgs: 2, MaxArgs: 3, Doc: "Compares two strings"},
+	{Name: "String", Type: FUNCTION, MinArgs: 2, MaxArgs: 2, Doc: "Returns a character repeated a number of times"},
+	{Name: "StrReverse", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Reverses a string"},
+	{Name: "Tan", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the tangent of an angle"},
+	{Name: "Time", Type: FUNCTION, MinArgs: 0, MaxArgs: 0, Doc: "Returns the current time"},
+	{Name: "Timer", Type: FUNCTION, MinArgs: 0, MaxArgs: 0, Doc: "Returns the number of seconds since midnight"},
+	{Name: "TimeSerial", Type: FUNCTION, MinArgs: 3, MaxArgs: 3, Doc: "Returns the time for an hour, minute and second"},
+	{Name: "TimeValue", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a string to a time"},
+	{Name: "Trim", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Removes leading and trailing spaces from a string"},
+	{Name: "TypeName", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the name of the type of a value"},
+	{Name: "UBound", Type: FUNCTION, MinArgs: 1, MaxArgs: 2, Doc: "Returns the largest index of an array"},
+	{Name: "UCase", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Converts a string to upper case"},
+	{Name: "UnEscape", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Decodes a string encoded with Escape"},
+	{Name: "VarType", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the subtype of a value, like vbString"},
+	{Name: "WeekDay", Type: FUNCTION, MinArgs: 1, MaxArgs: 2, Doc: "Returns the day of the week of a date"},
+	{Name: "WeekDayName", Type: FUNCTION, MinArgs: 1, MaxArgs: 3, Doc: "Returns the name of a day of the week"},
+	{Name: "Year", Type: FUNCTION, MinArgs: 1, MaxArgs: 1, Doc: "Returns the year of a date"},
+}
+
+// keywords are the names of special values.
+var keywords = []Symbol{
+	{Name: "Empty", Type: KEYWORD, Doc: "The value of an uninitialized variable"},
+	{Name: "Nothing", Type: KEYWORD, Doc: "An object reference to no object"},
+	{Name: "Null", Type: KEYWORD, Doc: "A value holding no valid data"},
+	{Name: "False", Type: KEYWORD_BOOL, Doc: "The Boolean value false"},
+	{Name: "True", Type: KEYWORD_BOOL, Doc: "The Boolean value true"},
+}
+
+// constants are the builtin constants.
+var constants = []Symbol{
+	{Name: "vbBlack", Type: COLOR_CONSTANT, Doc: "Black (&h00)"},
+	{Name: "vbRed", Type: COLOR_CONSTANT, Doc: "Red (&hFF)"},
+	{Name: "vbGreen", Type: COLOR_CONSTANT, Doc: "Green (&hFF00)"},
+	{Name: "vbYellow", Type: COLOR_CONSTANT, Doc: "Yellow (&hFFFF)"},
+	{Name: "vbBlue", Type: COLOR_CONSTANT, Doc: "Blue (&hFF0000)"},
+	{Name: "vbMagenta", Type: COLOR_CONSTANT, Doc: "Magenta (&hFF00FF)"},
+	{Name: "vbCyan", Type: COLOR_CONSTANT, Doc: "Cyan (&hFFFF00)"},
+	{Name: "vbWhite", Type: COLOR_CONSTANT, Doc: "White (&hFFFFFF)"},
+
+	{Name: "vbBinaryCompare", Type: COMPARE_CONSTANT, Doc: "Compares strings by character code (0)"},
+	{Name: "vbTextCompare", Type: COMPARE_CONSTANT, Doc: "Compares strings ignoring case (1)"},
+
+	{Name: "vbSunday", Type: DATE_CONSTANT, Doc: "Sunday (1)"},
+	{Name: "vbMonday", Type: DATE_CONSTANT, Doc: "Monday (2)"},
+	{Name: "vbTuesday", Type: DATE_CONSTANT, Doc: "Tuesday (3)"},
+	{Name: "vbWednesday", Type: DATE_CONSTANT, Doc: "Wednesday (4)"},
+	{Name: "vbThursday", Type: DATE_CONSTANT, Doc: "Thursday (5)"},
+	{Name: "vbFriday", Type: DATE_CONSTANT, Doc: "Friday (6)"},
+	{Name: "vbSaturday", Type: DATE_CONSTANT, Doc: "Saturday (7)"},
+	{Name: "vbUseSystemDayOfWeek", Type: DATE_CONSTANT, Doc: "Uses the first day of the week from the system settings (0)"},
+	{Name: "vbFirstJan1", Type: DATE_CONSTANT, Doc: "The first week is the week of January 1 (1)"},
+	{Name: "vbFirstFourDays", Type: DATE_CONSTANT, Doc: "The first week is the first with at least four days in the new year (2)"},
+	{Name: "vbFirstFullWeek", Type: DATE_CONSTANT, Doc: "The first week is the first full week of the year (3)"},
+
+	{Name: "vbGeneralDate", Type: DATEFORMAT_CONSTANT, Doc: "Shows the date and time, if present (0)"},
+	{Name: "vbLongDate", Type: DATEFORMAT_CONSTANT, Doc: "Shows the date in the long date format (1)"},
+	{Name: "vbShortDate", Type: DATEFORMAT_CONSTANT, Doc: "Shows the date in the short date format (2)"},
+	{Name: "vbLongTime", Type: DATEFORMAT_CONSTANT, Doc: "Shows the time in the long time format (3)"},
+	{Name: "vbShortTime", Type: DATEFORMAT_CONSTANT, Doc: "Shows the time in the short time format (4)"},
+
+	{Name: "vbObjectError", Type: MISC_CONSTANT, Doc: "Base of the error numbers of user-defined errors (&h80040000)"},
+
+	{Name: "vbOkOnly", Type: MSGBOX_CONSTANT, Doc: "Shows the OK button (0)"},
+	{Name: "vbOkCancel", Type: MSGBOX_CONSTANT, Doc: "Shows the OK and Cancel buttons (1)"},
+	{Name: "vbAbortRetryIgnore", Type: MSGBOX_CONSTANT, Doc: "Shows the Abort, Retry and Ignore buttons (2)"},
+	{Name: "vbYesNoCancel", Type: MSGBOX_CONSTANT, Doc: "Shows the Yes, No and Cancel buttons (3)"},
+	{Name: "vbYesNo", Type: MSGBOX_CONSTANT, Doc: "Shows the Yes and No buttons (4)"},
+	{Name: "vbRetryCancel", Type: MSGBOX_CONSTANT, Doc: "Shows the Retry and Cancel buttons (5)"},
+	{Name: "vbCritical", Type: MSGBOX_CONSTANT, Doc: "Shows the Critical Message icon (16)"},
+	{Name: "vbQuestion", Type: MSGBOX_CONSTANT, Doc: "Shows the Warning Query icon (32)"},
+	{Name: "vbExclamation", Type: MSGBOX_CONSTANT, Doc: "Shows the Warning Message icon (48)"},
+	{Name: "vbInformation", Type: MSGBOX_CONSTANT, Doc: "Shows the Information Message icon (64)"},
+	{Name: "vbDefaultButton1", Type: MSGBOX_CONSTANT, Doc: "The first button is the default (0)"},
+	{Name: "vbDefaultButton2", Type: MSGBOX_CONSTANT, Doc: "The second button is the default (256)"},
+	{Name: "vbDefaultButton3", Type: MSGBOX_CONSTANT, Doc: "The third button is the default (512)"},
+	{Name: "vbDefaultButton4", Type: MSGBOX_CONSTANT, Doc: "The fourth button is the default (768)"},
+	{Name: "vbApplicationModal", Type: MSGBOX_CONSTANT, Doc: "The user must respond before continuing in the application (0)"},
+	{Name: "vbSystemModal", Type: MSGBOX_CONSTANT, Doc: "The user must respond before continuing in any application (4096)"},
+	{Name: "vbOk", Type: MSGBOX_CONSTANT, Doc: "The OK button was clicked (1)"},
+	{Name: "vbCancel", Type: MSGBOX_CONSTANT, Doc: "The Cancel button was clicked (2)"},
+	{Name: "vbAbort", Type: MSGBOX_CONSTANT, Doc: "The Abort button was clicked (3)"},
+	{Name: "vbRetry", Type: MSGBOX_CONSTANT, Doc: "The Retry button was clicked (4)"},
+	{Name: "vbIgnore", Type: MSGBOX_CONSTANT, Doc: "The Ignore button was clicked (5)"},
+	{Name: "vbYes", Type: MSGBOX_CONSTANT, Doc: "The Yes button was clicked (6)"},
+	{Name: "vbNo", Type: MSGBOX_CONSTANT, Doc: "The No button was clicked (7)"},
+
+	{Name: "vbCr", Type: STRING_CONSTANT, Doc: "Carriage return, Chr(13)"},
+	{Name: "vbCrLf", Type: STRING_CONSTANT, Doc: "Carriage return and line feed, Chr(13) & Chr(10)"},
+	{Name: "vbFormFeed", Type: STRING_CONSTANT, Doc: "Form feed, Chr(12)"},
+	{Name: "vbLf", Type: STRING_CONSTANT, Doc: "Line feed, Chr(10)"},
+	{Name: "vbNewLine", Type: STRING_CONSTANT, Doc: "The line ending of the platform"},
+	{Name: "vbNullChar", Type: STRING_CONSTANT, Doc: "The character with code 0, Chr(0)"},
+	{Name: "vbNullString", Type: STRING_CONSTANT, Doc: "A string with value 0, for calling external procedures"},
+	{Name: "vbTab", Type: STRING_CONSTANT, Doc: "Horizontal tab, Chr(9)"},
+	{Name: "vbVerticalTab", Type: STRING_CONSTANT, Doc: "Vertical tab, Chr(11)"},
+
+	{Name: "vbUseDefault", Type: TRISTATE_CONSTANT, Doc: "Uses the default from the regional settings (-2)"},
+	{Name: "vbTrue", Type: TRISTATE_CONSTANT, Doc: "True (-1)"},
+	{Name: "vbFalse", Type: TRISTATE_CONSTANT, Doc: "False (0)"},
+
+	{Name: "vbEmpty", Type: VARTYPE_CONSTANT, Doc: "Uninitialized (0)"},
+	{Name: "vbNull", Type: VARTYPE_CONSTANT, Doc: "Null (1)"},
+	{Name: "vbInteger", Type: VARTYPE_CONSTANT, Doc: "Integer (2)"},
+	{Name: "vbLong", Type: VARTYPE_CONSTANT, Doc: "Long integer (3)"},
+	{Name: "vbSingle", Type: VARTYPE_CONSTANT, Doc: "Single precision floating point number (4)"},
+	{Name: "vbDouble", Type: VARTYPE_CONSTANT, Doc: "Double precision floating point number (5)"},
+	{Name: "vbCurrency", Type: VARTYPE_CONSTANT, Doc: "Currency (6)"},
+	{Name: "vbDate", Type: VARTYPE_CONSTANT, Doc: "Date (7)"},
+	{Name: "vbString", Type: VARTYPE_CONSTANT, Doc: "String (8)"},
+	{Name: "vbObject", Type: VARTYPE_CONSTANT, Doc: "Object (9)"},
+	{Name: "vbError", Type: VARTYPE_CONSTANT, Doc: "Error (10)"},
+	{Name: "vbBoolean", Type: VARTYPE_CONSTANT, Doc: "Boolean (11)"},
+	{Name: "vbVariant", Type: VARTYPE_CONSTANT, Doc: "Variant, for arrays of variants (12)"},
+	{Name: "vbDataObject", Type: VARTYPE_CONSTANT, Doc: "Data access object (13)"},
+	{Name: "vbDecimal", Type: VARTYPE_CONSTANT, Doc: "Decimal (14)"},
+	{Name: "vbByte", Type: VARTYPE_CONSTANT, Doc: "Byte (17)"},
+	{Name: "vbArray", Type: VARTYPE_CONSTANT, Doc: "Array (8192)"},
+}
@@ -0,0 +1,62 @@
+package vblexer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		want Symbol
+		ok   bool
+	}{
+		{"instr", Symbol{Name: "InStr", Type: FUNCTION, MinArgs: 2, MaxArgs: 4}, true},
+		{"LEN", Symbol{Name: "Len", Type: FUNCTION, MinArgs: 1, MaxArgs: 1}, true},
+		{"vbcrlf", Symbol{Name: "vbCrLf", Type: STRING_CONSTANT}, true},
+		{"elseif", Symbol{Name: "ElseIf", Type: STATEMENT}, true},
+		{"nothing", Symbol{Name: "Nothing", Type: KEYWORD}, true},
+		{"myVariable", Symbol{}, false},
+	}
+	for _, tt := range tests {
+		sym, ok := Lookup(tt.name)
+		sym.Doc = ""
+		if ok != tt.ok || sym != tt.want {
+			t.Errorf("Lookup(%q) = %+v, %v; want %+v, %v", tt.name, sym, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	syms := Symbols()
+	if !slices.IsSortedFunc(syms, func(a, b Symbol) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	}) {
+		t.Error("symbols not sorted")
+	}
+	for _, sym := range syms {
+		if sym.Doc == "" {
+			t.Errorf("%s: no doc", sym.Name)
+		}
+		if got, ok := Lookup(sym.Name); !ok || got != sym {
+			t.Errorf("%s: Lookup gives %+v", sym.Name, got)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(
+		Symbol{Name: "FormatCurrencyEx", Type: FUNCTION, MinArgs: 1, MaxArgs: -1, Doc: "Formats an amount"},
+		Symbol{Name: "siteBlue", Type: COLOR_CONSTANT, Doc: "The blue of the site"},
+	)
+	list := lexString("x = formatcurrencyex(a, b) & SITEBLUE", vbscanner.VBS_MODE, nil)
+	if len(list) != 10 || list[2].Type != FUNCTION || list[2].Value != "FormatCurrencyEx" || list[9].Type != COLOR_CONSTANT || list[9].Value != "siteBlue" {
+		t.Errorf("got %s", describe(list))
+	}
+	if sym, ok := Lookup("FORMATCURRENCYEX"); !ok || sym.MaxArgs != -1 {
+		t.Errorf("Lookup after Register = %+v, %v", sym, ok)
+	}
+}
@@ -0,0 +1,161 @@
+package vblexer
+
+import (
+	"strings"
+)
+
+//go:generate stringer -type=Compound
+
+// Compound identifies a statement of several words, like End If, which is
+// lexed as a single COMPOUND token in compound mode.
+type Compound int
+
+// Compound statements
+const (
+	EndIf Compound = iota
+	EndFunction
+	EndSub
+	EndClass
+	EndProperty
+	EndSelect
+	EndWith
+	ExitDo
+	ExitFor
+	ExitFunction
+	ExitSub
+	ExitProperty
+	OnErrorResumeNext
+	OnErrorGoTo0
+	SelectCase
+	CaseElse
+	PropertyGet
+	PropertyLet
+	PropertySet
+	ForEach
+	OptionExplicit
+)
+
+// compounds holds the words of each compound statement, as the values of
+// the tokens they are made of.
+var compounds = [...][]interface{}{
+	EndIf:             {"End", "If"},
+	EndFunction:       {"End", "Function"},
+	EndSub:            {"End", "Sub"},
+	EndClass:          {"End", "Class"},
+	EndProperty:       {"End", "Property"},
+	EndSelect:         {"End", "Select"},
+	EndWith:           {"End", "With"},
+	ExitDo:            {"Exit", "Do"},
+	ExitFor:           {"Exit", "For"},
+	ExitFunction:      {"Exit", "Function"},
+	ExitSub:           {"Exit", "Sub"},
+	ExitProperty:      {"Exit", "Property"},
+	OnErrorResumeNext: {"On", "Error", "Resume", "Next"},
+	OnErrorGoTo0:      {"On", "Error", "GoTo", int16(0)},
+	SelectCase:        {"Select", "Case"},
+	CaseElse:          {"Case", "Else"},
+	PropertyGet:       {"Property", "Get"},
+	PropertyLet:       {"Property", "Let"},
+	PropertySet:       {"Property", "Set"},
+	ForEach:           {"For", "Each"},
+	OptionExplicit:    {"Option", "Explicit"},
+}
+
+// Statement returns the canonical spelling of the statement, like "End If".
+func (c Compound) Statement() string {
+	words := make([]string, len(compounds[c]))
+	for i, w := range compounds[c] {
+		if s, ok := w.(string); ok {
+			sym, _ := Lookup(s)
+			words[i] = sym.Name
+		} else {
+			words[i] = "0"
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// SetCompound turns compound mode on or off. In compound mode statements of
+// several words on the same line, like End If, Exit For or On Error Resume
+// Next, are returned as a single COMPOUND token whose value is a Compound.
+// Its Raw value is the words as written, separated by spaces. The parser
+// expects compound mode to be off. It should be called before the first
+// token is read.
+func (lex *Lex) SetCompound(on bool) {
+	lex.compound = on
+}
+
+// matchWord returns true if t is the given word of a compound statement.
+func matchWord(t Token, word interface{}) bool {
+	if _, ok := word.(string); ok {
+		return t.Type == STATEMENT && t.Value == word
+	}
+	return t.Type == INT && t.Value == word
+}
+
+// matchTail returns true if the last n tokens of q are the first n words.
+func matchTail(q []Token, words []interface{}, n int) bool {
+	if n > len(q) || n > len(words) {
+		return false
+	}
+	tail := q[len(q)-n:]
+	for i, t := range tail {
+		if !matchWord(t, words[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// combine merges the tokens at the end of the queue if they form a compound
+// statement, and otherwise counts the tokens at the end of the queue that
+// may begin one, which fill holds back until it knows.
+func (lex *Lex) combine() {
+	lex.held = 0
+	if !lex.compound {
+		return
+	}
+	for c, words := range compounds {
+		if matchTail(lex.q, words, len(words)) {
+			lex.merge(Compound(c), len(words))
+			return
+		}
+	}
+	for _, words := range compounds {
+		for n := len(words) - 1; n > lex.held; n-- {
+			if matchTail(lex.q, words, n) {
+				lex.held = n
+				break
+			}
+		}
+	}
+}
+
+// merge replaces the last n tokens in the queue with a COMPOUND token.
+func (lex *Lex) merge(c Compound, n int) {
+	tail := lex.q[len(lex.q)-n:]
+	first, last := tail[0], tail[n-1]
+	t := Token{
+		Type:     COMPOUND,
+		Value:    c,
+		Start:    first.Start,
+		End:      last.End,
+		Leading:  first.Leading,
+		Trailing: last.Trailing,
+		line:     last.line,
+	}
+	var raw, text strings.Builder
+	for i, w := range tail {
+		if i > 0 {
+			raw.WriteString(" ")
+			text.WriteString(tail[i-1].Trailing + w.Leading)
+		}
+		raw.WriteString(w.Raw)
+		text.WriteString(w.Text)
+	}
+	t.Raw = raw.String()
+	if lex.lossless {
+		t.Text = text.String()
+	}
+	lex.q = append(lex.q[:len(lex.q)-n], t)
+}
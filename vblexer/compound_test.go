@@ -0,0 +1,56 @@
+package vblexer
+
+import (
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+func TestCompound(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // tokens in compound mode
+	}{
+		{"end  if", "COMPOUND end if"},
+		{"On Error Resume Next", "COMPOUND On Error Resume Next"},
+		{"On Error GoTo 0", "COMPOUND On Error GoTo 0"},
+		{"Select Case x", "COMPOUND Select Case, IDENTIFIER x"},
+		{"Case Else", "COMPOUND Case Else"},
+		{"Exit For", "COMPOUND Exit For"},
+		{"Public Property Get P", "STATEMENT Public, COMPOUND Property Get, IDENTIFIER P"},
+		{"For Each v In list", "COMPOUND For Each, IDENTIFIER v, STATEMENT In, IDENTIFIER list"},
+		{"End", "STATEMENT End"},
+		{"On Error", "STATEMENT On, STATEMENT Error"},
+		{"End _\n If", "STATEMENT End, CONTINUATION _, EOL \n, STATEMENT If"},
+		{"End: If", "STATEMENT End, EOL :, STATEMENT If"},
+	}
+	for _, tt := range tests {
+		list := lexString(tt.in, vbscanner.VBS_MODE, func(lex *Lex) { lex.SetCompound(true) })
+		if got := describe(list); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	list := lexString("x: End If", vbscanner.VBS_MODE, func(lex *Lex) { lex.SetCompound(true) })
+	if tok := list[len(list)-1]; tok.Value != EndIf || tok.Start.Column != 4 || tok.End.Column != 10 {
+		t.Errorf("End If: got %v at %v-%v", tok.Value, tok.Start, tok.End)
+	}
+	if list := lexString("End If", vbscanner.VBS_MODE, nil); describe(list) != "STATEMENT End, STATEMENT If" {
+		t.Errorf("compound mode off: got %s", describe(list))
+	}
+}
+
+func TestCompoundStatement(t *testing.T) {
+	tests := map[Compound]string{
+		EndIf:             "End If",
+		OnErrorResumeNext: "On Error Resume Next",
+		OnErrorGoTo0:      "On Error GoTo 0",
+		PropertyGet:       "Property Get",
+		OptionExplicit:    "Option Explicit",
+	}
+	for c, want := range tests {
+		if got := c.Statement(); got != want {
+			t.Errorf("%v: got %q, want %q", c, got, want)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package vblexer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DateOrder is the order of the day, month and year in date literals written
+// with numbers only, like #1/2/2003#. VBScript takes it from the locale of
+// the session.
+type DateOrder int
+
+// Date orders
+const (
+	MonthDayYear DateOrder = iota // 12/31/1999, as in the United States
+	DayMonthYear                  // 31/12/1999, as in most of Europe
+	YearMonthDay                  // 1999/12/31, as in East Asia
+)
+
+// ErrInvalidDate is wrapped by the error value of the ERROR token that takes
+// the place of an invalid date literal.
+var ErrInvalidDate = errors.New("invalid date literal")
+
+// lcidOrders gives the date order of locales that differ from the usual
+// order of their language.
+var lcidOrders = map[int]DateOrder{
+	1033:  MonthDayYear, // en-US
+	9225:  MonthDayYear, // en-029
+	13321: MonthDayYear, // en-PH
+	3084:  YearMonthDay, // fr-CA
+}
+
+// languageOrders gives the date order of languages that do not put the day
+// first, by primary language ID.
+var languageOrders = map[int]DateOrder{
+	0x04: YearMonthDay, // Chinese
+	0x0e: YearMonthDay, // Hungarian
+	0x11: YearMonthDay, // Japanese
+	0x12: YearMonthDay, // Korean
+	0x1d: YearMonthDay, // Swedish
+	0x27: YearMonthDay, // Lithuanian
+	0x29: YearMonthDay, // Persian
+}
+
+// lcidOrder returns the date order of a Windows locale ID, like 1033 for
+// English (United States) or 2057 for English (United Kingdom).
+func lcidOrder(lcid int) DateOrder {
+	if o, ok := lcidOrders[lcid]; ok {
+		return o
+	}
+	if o, ok := languageOrders[lcid&0x3ff]; ok {
+		return o
+	}
+	return DayMonthYear
+}
+
+// SetDateOrder sets the order of the day, month and year in date literals
+// written with numbers only. The default is MonthDayYear. A page directive
+// with an LCID attribute changes it for the rest of the page.
+func (lex *Lex) SetDateOrder(order DateOrder) {
+	lex.order = order
+	lex.dates = order
+}
+
+// SetLCID sets the order of the day, month and year in date literals from a
+// Windows locale ID, like 2057 for English (United Kingdom).
+func (lex *Lex) SetLCID(lcid int) {
+	lex.SetDateOrder(lcidOrder(lcid))
+}
+
+// SetLocation sets the time zone of date literals. The default is
+// America/New_York, or the local time zone if that is not available.
+func (lex *Lex) SetLocation(loc *time.Location) {
+	lex.loc = loc
+}
+
+// defaultLocation returns the time zone of date literals used by earlier versions.
+var defaultLocation = sync.OnceValue(func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+})
+
+// location returns the time zone of date literals.
+func (lex *Lex) location() *time.Location {
+	if lex.loc != nil {
+		return lex.loc
+	}
+	return defaultLocation()
+}
+
+// months holds the English month names. Three letter abbreviations are also accepted.
+var months = [...]string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"}
+
+// month returns the month named by word, or 0.
+func month(word string) time.Month {
+	word = strings.ToLower(word)
+	for i, m := range months {
+		if word == m || len(word) == 3 && strings.HasPrefix(m, word) {
+			return time.Month(i + 1)
+		}
+	}
+	return 0
+}
+
+// datePart is a number or word in a date literal.
+type datePart struct {
+	s     string
+	num   bool
+	colon bool // followed by ":"
+}
+
+// splitDate splits a date literal into numbers and words. The separators
+// "/", "-", ",", "." and spaces are dropped, and ":" is noted on the part
+// before it.
+func splitDate(s string) ([]datePart, error) {
+	var parts []datePart
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+		switch {
+		case r == ':':
+			if len(parts) == 0 || !parts[len(parts)-1].num || parts[len(parts)-1].colon {
+				return nil, fmt.Errorf("misplaced \":\"")
+			}
+			parts[len(parts)-1].colon = true
+			i++
+		case strings.ContainsRune("/-,. \t", r):
+			i++
+		case r >= '0' && r <= '9':
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			parts = append(parts, datePart{s: s[i:j], num: true})
+			i = j
+		case isLetter(s[i]):
+			j := i
+			for j < len(s) && isLetter(s[j]) {
+				j++
+			}
+			parts = append(parts, datePart{s: s[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q", s[i:i+1])
+		}
+	}
+	return parts, nil
+}
+
+// isLetter returns true for the ASCII letters.
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// yearLike returns true if a number can only be a year.
+func yearLike(s string) bool {
+	n, _ := strconv.Atoi(s)
+	return len(s) > 2 || n > 31
+}
+
+// year returns the year written as s. Two digit years from 30 to 99 are in
+// the 1900s and the others in the 2000s, as in VBScript.
+func year(s string) int {
+	n, _ := strconv.Atoi(s)
+	if len(s) <= 2 {
+		if n < 30 {
+			return 2000 + n
+		}
+		return 1900 + n
+	}
+	return n
+}
+
+// validDate returns true if the day exists in the month.
+func validDate(y int, m time.Month, d int) bool {
+	return m >= time.January && m <= time.December && d >= 1 && d <= time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// parseDate parses the text of a date literal, between the # characters.
+// The date may be written with numbers in the given order, or with an
+// English month name in any position, like #31-Dec-1999# or #December 31,
+// 1999#. A missing year is the current one and a missing day is the first
+// of the month. The time may follow or precede the date, with or without
+// seconds and AM or PM, like #9:26 PM# or #12:00 AM#. A time without a date
+// is on January 1 of year 0, as in earlier versions.
+func parseDate(s string, order DateOrder, loc *time.Location) (time.Time, error) {
+	parts, err := splitDate(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var (
+		nums          []string
+		mon           time.Month
+		hasTime       bool
+		hour, minutes int
+		seconds       int
+	)
+	for i := 0; i < len(parts); i++ {
+		p := parts[i]
+		ampm := func(j int) bool {
+			if j >= len(parts) || parts[j].num {
+				return false
+			}
+			switch strings.ToUpper(parts[j].s) {
+			case "AM", "A", "PM", "P":
+				return true
+			}
+			return false
+		}
+		switch {
+		case p.num && (p.colon || ampm(i+1)):
+			if hasTime {
+				return time.Time{}, fmt.Errorf("more than one time")
+			}
+			hasTime = true
+			hour, _ = strconv.Atoi(p.s)
+			for _, v := range []*int{&minutes, &seconds} {
+				if !parts[i].colon {
+					break
+				}
+				if i+1 >= len(parts) || !parts[i+1].num {
+					return time.Time{}, fmt.Errorf("missing minutes or seconds")
+				}
+				i++
+				*v, _ = strconv.Atoi(parts[i].s)
+			}
+			if parts[i].colon {
+				return time.Time{}, fmt.Errorf("misplaced \":\"")
+			}
+			if minutes > 59 || seconds > 59 {
+				return time.Time{}, fmt.Errorf("minutes or seconds out of range")
+			}
+			if ampm(i + 1) {
+				i++
+				if hour < 1 || hour > 12 {
+					return time.Time{}, fmt.Errorf("hour out of range")
+				}
+				hour %= 12
+				if strings.HasPrefix(strings.ToUpper(parts[i].s), "P") {
+					hour += 12
+				}
+			} else if hour > 23 {
+				return time.Time{}, fmt.Errorf("hour out of range")
+			}
+		case p.num:
+			nums = append(nums, p.s)
+		default:
+			m := month(p.s)
+			if m == 0 {
+				return time.Time{}, fmt.Errorf("unknown word %q", p.s)
+			}
+			if mon != 0 {
+				return time.Time{}, fmt.Errorf("more than one month")
+			}
+			mon = m
+		}
+	}
+
+	if mon == 0 && len(nums) == 0 {
+		if !hasTime {
+			return time.Time{}, fmt.Errorf("no date or time")
+		}
+		return time.Date(0, time.January, 1, hour, minutes, seconds, 0, loc), nil
+	}
+
+	var y, d int
+	m := mon
+	swap := false // whether the day and month were both written as numbers
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	switch {
+	case mon != 0 && len(nums) == 2:
+		if yearLike(nums[0]) {
+			y, d = year(nums[0]), atoi(nums[1])
+		} else {
+			d, y = atoi(nums[0]), year(nums[1])
+		}
+	case mon != 0 && len(nums) == 1:
+		if yearLike(nums[0]) {
+			y, d = year(nums[0]), 1
+		} else {
+			y, d = time.Now().In(loc).Year(), atoi(nums[0])
+		}
+	case mon == 0 && len(nums) == 3:
+		a, b, c := atoi(nums[0]), atoi(nums[1]), atoi(nums[2])
+		swap = true
+		switch {
+		case yearLike(nums[0]) || order == YearMonthDay:
+			y, m, d = year(nums[0]), time.Month(b), c
+		case order == DayMonthYear:
+			d, m, y = a, time.Month(b), year(nums[2])
+		default:
+			m, d, y = time.Month(a), b, year(nums[2])
+		}
+	case mon == 0 && len(nums) == 2:
+		a, b := atoi(nums[0]), atoi(nums[1])
+		switch {
+		case yearLike(nums[0]):
+			y, m, d = year(nums[0]), time.Month(b), 1
+		case yearLike(nums[1]):
+			m, y, d = time.Month(a), year(nums[1]), 1
+		case order == DayMonthYear:
+			y, d, m, swap = time.Now().In(loc).Year(), a, time.Month(b), true
+		default:
+			y, m, d, swap = time.Now().In(loc).Year(), time.Month(a), b, true
+		}
+	default:
+		return time.Time{}, fmt.Errorf("not a date")
+	}
+	if !validDate(y, m, d) && swap && validDate(y, time.Month(d), int(m)) {
+		// like VBScript, swap the day and month when only that makes a date
+		m, d = time.Month(d), int(m)
+	}
+	if !validDate(y, m, d) {
+		return time.Time{}, fmt.Errorf("day or month out of range")
+	}
+	if y < 100 || y > 9999 {
+		return time.Time{}, fmt.Errorf("year out of range")
+	}
+	return time.Date(y, m, d, hour, minutes, seconds, 0, loc), nil
+}
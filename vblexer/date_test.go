@@ -0,0 +1,78 @@
+package vblexer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	loc := time.UTC
+	date := func(y int, m time.Month, d, h, min, sec int) time.Time {
+		return time.Date(y, m, d, h, min, sec, 0, loc)
+	}
+	tests := []struct {
+		in    string
+		order DateOrder
+		want  time.Time
+		err   bool
+	}{
+		// order of numbers
+		{"1/2/2003", MonthDayYear, date(2003, 1, 2, 0, 0, 0), false},
+		{"1/2/2003", DayMonthYear, date(2003, 2, 1, 0, 0, 0), false},
+		{"2003/1/2", YearMonthDay, date(2003, 1, 2, 0, 0, 0), false},
+		{"2003-01-02", MonthDayYear, date(2003, 1, 2, 0, 0, 0), false},
+		{"13/1/2003", MonthDayYear, date(2003, 1, 13, 0, 0, 0), false},
+		{"1/13/2003", DayMonthYear, date(2003, 1, 13, 0, 0, 0), false},
+		{"32/13/2003", MonthDayYear, time.Time{}, true},
+
+		// two-digit years
+		{"1/2/03", MonthDayYear, date(2003, 1, 2, 0, 0, 0), false},
+		{"1/2/29", MonthDayYear, date(2029, 1, 2, 0, 0, 0), false},
+		{"1/2/30", MonthDayYear, date(1930, 1, 2, 0, 0, 0), false},
+		{"1/2/99", DayMonthYear, date(1999, 2, 1, 0, 0, 0), false},
+
+		// month names
+		{"31-Dec-1999", MonthDayYear, date(1999, 12, 31, 0, 0, 0), false},
+		{"December 31, 1999", DayMonthYear, date(1999, 12, 31, 0, 0, 0), false},
+		{"Dec 1999", MonthDayYear, date(1999, 12, 1, 0, 0, 0), false},
+
+		// times
+		{"1/2/2003 3:04 PM", MonthDayYear, date(2003, 1, 2, 15, 4, 0), false},
+		{"1/2/2003 3:04:05 pm", MonthDayYear, date(2003, 1, 2, 15, 4, 5), false},
+		{"1/2/2003 3:04 AM", MonthDayYear, date(2003, 1, 2, 3, 4, 0), false},
+		{"1/2/2003 15:04", MonthDayYear, date(2003, 1, 2, 15, 4, 0), false},
+		{"12:00 AM", MonthDayYear, date(0, 1, 1, 0, 0, 0), false},
+		{"12:30 PM", MonthDayYear, date(0, 1, 1, 12, 30, 0), false},
+		{"9:26 P", MonthDayYear, date(0, 1, 1, 21, 26, 0), false},
+		{"25:00", MonthDayYear, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.in, tt.order, loc)
+		if (err != nil) != tt.err {
+			t.Errorf("parseDate(%q, %v) error = %v, want error %v", tt.in, tt.order, err, tt.err)
+			continue
+		}
+		if !tt.err && !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q, %v) = %v, want %v", tt.in, tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestLCIDOrder(t *testing.T) {
+	tests := []struct {
+		lcid int
+		want DateOrder
+	}{
+		{1033, MonthDayYear}, // en-US
+		{2057, DayMonthYear}, // en-GB
+		{1036, DayMonthYear}, // fr-FR
+		{3084, YearMonthDay}, // fr-CA
+		{1041, YearMonthDay}, // ja-JP
+		{2052, YearMonthDay}, // zh-CN
+	}
+	for _, tt := range tests {
+		if got := lcidOrder(tt.lcid); got != tt.want {
+			t.Errorf("lcidOrder(%d) = %v, want %v", tt.lcid, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package vblexer
+
+import (
+	"strings"
+)
+
+// directiveNames gives the usual spelling of the attributes IIS recognizes
+// in a page directive.
+var directiveNames = map[string]string{
+	"language":           "Language",
+	"codepage":           "CodePage",
+	"enablesessionstate": "EnableSessionState",
+	"transaction":        "Transaction",
+	"lcid":               "LCID",
+}
+
+// ParseDirective parses the attributes of a page directive, like
+// Language="VBScript" CodePage=65001, into a map. Quotes around values are
+// removed. The names IIS recognizes are given their usual spelling, and other
+// names are kept as written. An attribute without a value maps to "".
+func ParseDirective(text string) map[string]string {
+	attrs := make(map[string]string)
+	for {
+		text = strings.TrimLeft(text, " \t\r\n")
+		if text == "" {
+			return attrs
+		}
+		i := strings.IndexAny(text, "= \t\r\n")
+		if i < 0 {
+			i = len(text)
+		}
+		name := text[:i]
+		text = strings.TrimLeft(text[i:], " \t\r\n")
+		value := ""
+		if strings.HasPrefix(text, "=") {
+			text = strings.TrimLeft(text[1:], " \t\r\n")
+			if strings.HasPrefix(text, `"`) {
+				j := strings.IndexByte(text[1:], '"')
+				if j < 0 {
+					j = len(text) - 1
+				}
+				value = text[1 : j+1]
+				text = text[min(j+2, len(text)):]
+			} else {
+				j := strings.IndexAny(text, " \t\r\n")
+				if j < 0 {
+					j = len(text)
+				}
+				value, text = text[:j], text[j:]
+			}
+		}
+		if name == "" {
+			continue
+		}
+		if n, ok := directiveNames[strings.ToLower(name)]; ok {
+			name = n
+		}
+		attrs[name] = value
+	}
+}
@@ -0,0 +1,43 @@
+package vblexer
+
+import (
+	"fmt"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// Error describes a problem found while lexing, like an unterminated string
+// or an invalid date literal.
+type Error struct {
+	Filename string
+	Pos      vbscanner.Position
+	Msg      string
+	Err      error // underlying error, like a *vbscanner.ScanError
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%s: %s", e.Filename, e.Pos, e.Msg)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errors returns the problems found in the tokens lexed so far, including
+// those peeked at. Lexing continues after a problem: the scanner resumes
+// after the malformed text, and literals that cannot be converted become
+// ERROR tokens.
+func (lex *Lex) Errors() []*Error {
+	return lex.errs
+}
+
+// report records a problem at pos.
+func (lex *Lex) report(pos vbscanner.Position, err error) {
+	msg := err.Error()
+	if se, ok := err.(*vbscanner.ScanError); ok {
+		pos, msg = se.Pos, se.Msg
+	}
+	lex.errs = append(lex.errs, &Error{Filename: lex.Filename, Pos: pos, Msg: msg, Err: err})
+}
@@ -0,0 +1,77 @@
+package vblexer
+
+// intrinsics are the objects ASP provides to every page, and the Err object.
+var intrinsics = []Symbol{
+	{Name: "Application", Type: INTRINSIC_OBJECT, Doc: "State shared by all users of an application"},
+	{Name: "Err", Type: INTRINSIC_OBJECT, Doc: "Information about the last run-time error"},
+	{Name: "ObjectContext", Type: INTRINSIC_OBJECT, Doc: "Commits or aborts the transaction of a page"},
+	{Name: "Request", Type: INTRINSIC_OBJECT, Doc: "The HTTP request"},
+	{Name: "Response", Type: INTRINSIC_OBJECT, Doc: "The HTTP response"},
+	{Name: "Server", Type: INTRINSIC_OBJECT, Doc: "Utility methods of the web server"},
+	{Name: "Session", Type: INTRINSIC_OBJECT, Doc: "State kept for a single user"},
+}
+
+// intrinsicMembers are the members of the intrinsic objects, named like Object.Member.
+var intrinsicMembers = []Symbol{
+	{Name: "Application.Contents", Type: INTRINSIC_MEMBER, Doc: "Collection of the application variables"},
+	{Name: "Application.Lock", Type: INTRINSIC_MEMBER, Doc: "Stops other users from changing application variables"},
+	{Name: "Application.StaticObjects", Type: INTRINSIC_MEMBER, Doc: "Collection of the objects declared with <object> in global.asa"},
+	{Name: "Application.Unlock", Type: INTRINSIC_MEMBER, Doc: "Lets other users change application variables"},
+
+	{Name: "Err.Clear", Type: INTRINSIC_MEMBER, Doc: "Clears the error"},
+	{Name: "Err.Description", Type: INTRINSIC_MEMBER, Doc: "Description of the error"},
+	{Name: "Err.HelpContext", Type: INTRINSIC_MEMBER, Doc: "Context ID of a help topic for the error"},
+	{Name: "Err.HelpFile", Type: INTRINSIC_MEMBER, Doc: "Path of a help file for the error"},
+	{Name: "Err.Number", Type: INTRINSIC_MEMBER, Doc: "Number of the error, or 0 if there is none"},
+	{Name: "Err.Raise", Type: INTRINSIC_MEMBER, Doc: "Raises an error"},
+	{Name: "Err.Source", Type: INTRINSIC_MEMBER, Doc: "Name of the object or application that raised the error"},
+
+	{Name: "ObjectContext.SetAbort", Type: INTRINSIC_MEMBER, Doc: "Aborts the transaction"},
+	{Name: "ObjectContext.SetComplete", Type: INTRINSIC_MEMBER, Doc: "Lets the transaction commit"},
+
+	{Name: "Request.BinaryRead", Type: INTRINSIC_MEMBER, Doc: "Reads bytes of the request body"},
+	{Name: "Request.ClientCertificate", Type: INTRINSIC_MEMBER, Doc: "Collection of the fields of the client certificate"},
+	{Name: "Request.Cookies", Type: INTRINSIC_MEMBER, Doc: "Collection of the cookies sent with the request"},
+	{Name: "Request.Form", Type: INTRINSIC_MEMBER, Doc: "Collection of the form fields posted in the request body"},
+	{Name: "Request.QueryString", Type: INTRINSIC_MEMBER, Doc: "Collection of the query string parameters"},
+	{Name: "Request.ServerVariables", Type: INTRINSIC_MEMBER, Doc: "Collection of the server environment variables"},
+	{Name: "Request.TotalBytes", Type: INTRINSIC_MEMBER, Doc: "Size of the request body in bytes"},
+
+	{Name: "Response.AddHeader", Type: INTRINSIC_MEMBER, Doc: "Adds an HTTP header"},
+	{Name: "Response.AppendToLog", Type: INTRINSIC_MEMBER, Doc: "Adds a string to the web server log entry"},
+	{Name: "Response.BinaryWrite", Type: INTRINSIC_MEMBER, Doc: "Writes bytes without conversion"},
+	{Name: "Response.Buffer", Type: INTRINSIC_MEMBER, Doc: "Whether output is buffered until the page is done"},
+	{Name: "Response.CacheControl", Type: INTRINSIC_MEMBER, Doc: "Whether proxy servers may cache the output"},
+	{Name: "Response.Charset", Type: INTRINSIC_MEMBER, Doc: "Character set added to the content type"},
+	{Name: "Response.Clear", Type: INTRINSIC_MEMBER, Doc: "Discards buffered output"},
+	{Name: "Response.CodePage", Type: INTRINSIC_MEMBER, Doc: "Code page used to encode the output"},
+	{Name: "Response.ContentType", Type: INTRINSIC_MEMBER, Doc: "Content type of the response"},
+	{Name: "Response.Cookies", Type: INTRINSIC_MEMBER, Doc: "Collection of the cookies to set"},
+	{Name: "Response.End", Type: INTRINSIC_MEMBER, Doc: "Stops processing the page and sends the output"},
+	{Name: "Response.Expires", Type: INTRINSIC_MEMBER, Doc: "Minutes before a cached page expires"},
+	{Name: "Response.ExpiresAbsolute", Type: INTRINSIC_MEMBER, Doc: "Date and time a cached page expires"},
+	{Name: "Response.Flush", Type: INTRINSIC_MEMBER, Doc: "Sends buffered output"},
+	{Name: "Response.IsClientConnected", Type: INTRINSIC_MEMBER, Doc: "Whether the client is still connected"},
+	{Name: "Response.LCID", Type: INTRINSIC_MEMBER, Doc: "Locale used to format dates and numbers"},
+	{Name: "Response.PICS", Type: INTRINSIC_MEMBER, Doc: "Value of the PICS-Label header"},
+	{Name: "Response.Redirect", Type: INTRINSIC_MEMBER, Doc: "Redirects the client to another URL"},
+	{Name: "Response.Status", Type: INTRINSIC_MEMBER, Doc: "HTTP status line"},
+	{Name: "Response.Write", Type: INTRINSIC_MEMBER, Doc: "Writes a string to the output"},
+
+	{Name: "Server.CreateObject", Type: INTRINSIC_MEMBER, Doc: "Creates a COM object"},
+	{Name: "Server.Execute", Type: INTRINSIC_MEMBER, Doc: "Runs another page and includes its output"},
+	{Name: "Server.GetLastError", Type: INTRINSIC_MEMBER, Doc: "Returns an ASPError object for the last error"},
+	{Name: "Server.HTMLEncode", Type: INTRINSIC_MEMBER, Doc: "Escapes a string for HTML"},
+	{Name: "Server.MapPath", Type: INTRINSIC_MEMBER, Doc: "Converts a virtual path to a physical path"},
+	{Name: "Server.ScriptTimeout", Type: INTRINSIC_MEMBER, Doc: "Seconds a script may run"},
+	{Name: "Server.Transfer", Type: INTRINSIC_MEMBER, Doc: "Continues processing in another page"},
+	{Name: "Server.URLEncode", Type: INTRINSIC_MEMBER, Doc: "Escapes a string for a URL"},
+
+	{Name: "Session.Abandon", Type: INTRINSIC_MEMBER, Doc: "Ends the session after the page is done"},
+	{Name: "Session.CodePage", Type: INTRINSIC_MEMBER, Doc: "Code page used to encode the output"},
+	{Name: "Session.Contents", Type: INTRINSIC_MEMBER, Doc: "Collection of the session variables"},
+	{Name: "Session.LCID", Type: INTRINSIC_MEMBER, Doc: "Locale used to format dates and numbers"},
+	{Name: "Session.SessionID", Type: INTRINSIC_MEMBER, Doc: "ID of the session"},
+	{Name: "Session.StaticObjects", Type: INTRINSIC_MEMBER, Doc: "Collection of the session objects declared with <object> in global.asa"},
+	{Name: "Session.Timeout", Type: INTRINSIC_MEMBER, Doc: "Minutes of inactivity before the session ends"},
+}
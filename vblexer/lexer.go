@@ -4,6 +4,7 @@ package vblexer
 import (
 	"fmt"
 	"io"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -20,7 +21,7 @@ type TokenType int
 // Token types
 const (
 	EOF                 TokenType = iota // end of file
-	STATEMENT                            // language statements (reserved words)
+	STATEMENT                            // language statements (reserved words), with the name given by Symbols as value, like ElseIf
 	FUNCTION                             // builtin functions
 	KEYWORD                              // keywords - nothing, null, empty
 	KEYWORD_BOOL                         // boolean keywords - true and false
@@ -35,13 +36,13 @@ const (
 	VARTYPE_CONSTANT                     // constants for variant types
 	IDENTIFIER                           // identifiers
 	STRING                               // string literals
-	INT                                  // integer literals
-	FLOAT                                // float literals
+	INT                                  // integer literals, with an int16 (Integer) or int32 (Long) value
+	FLOAT                                // float literals and integers too large for a Long, with a float64 (Double) value
 	DATE                                 // date literals
 	COMMENT                              // comments
 	HTML                                 // HTML fragments in the ASP
 	CHAR                                 // random characters
-	EOL                                  // end of line
+	EOL                                  // end of line, with the value ":" or "\n" and the line ending as written in Raw
 	OP                                   // operator
 	CONTINUATION                         // continuation character (underscore)
 	FILE_INCLUDE                         // file include
@@ -49,7 +50,16 @@ const (
 	LIST_SEP                             // list separator (comma)
 	PAREN_OPEN                           // function invoke, array index, grouping (parens)
 	PAREN_CLOSE                          // function invoke, array index, grouping (parens)
-	FIELD_SEP                            // field separator (dot) - usually part of identifier except after parens
+	FIELD_SEP                            // field separator (dot) for member access, like rs.Fields - part of the identifier in dotted mode
+	OUTPUT                               // start of an output block (<%=)
+	DIRECTIVE                            // page directive (<%@ ... %>), with the attributes as a map[string]string
+	SCRIPT_TAG                           // opening or closing tag of a server script block (<script runat="server">)
+	INTRINSIC_OBJECT                     // ASP intrinsic objects, like Response, and the Err object
+	INTRINSIC_MEMBER                     // members of the intrinsic objects, like Response.Write
+	ADO_CONSTANT                         // ADO constants, like adOpenStatic
+	FSO_CONSTANT                         // FileSystemObject constants, like ForReading
+	COMPOUND                             // statement of several words, like End If, in compound mode, with a Compound value
+	ERROR                                // text that cannot be read, like an invalid date literal, with an error value and the text in Raw, without delimiters as for the literal
 )
 
 // Lex uses a scanner to read and classify VBScript tokens
@@ -57,7 +67,20 @@ type Lex struct {
 	s        vbscanner.Scanner
 	Filename string
 	Line     int
-	q        []qitem
+	Start    vbscanner.Position // start of the last token returned
+	End      vbscanner.Position // end of the last token returned
+	line     int                // line number after the last token lexed
+	q        []Token            // tokens lexed but not yet returned
+	lossless bool               // whether to keep the original text of tokens
+	member   bool               // the last token lexed was a FIELD_SEP, so a name is a member name
+	object   string             // the intrinsic object before that FIELD_SEP, if any
+	last     Token              // the last token lexed
+	compound bool               // whether to combine statements of several words
+	held     int                // tokens at the end of q that may begin a compound statement
+	order    DateOrder          // order of numeric dates set for the lexer
+	dates    DateOrder          // order of numeric dates in the page, which may have an LCID
+	loc      *time.Location     // time zone of date literals, or nil for the default
+	errs     []*Error           // problems found so far
 }
 
 // Init prepares the lexer for use.
@@ -65,19 +88,135 @@ func (lex *Lex) Init(src io.Reader, fname string, initialMode vbscanner.Mode) {
 	lex.s.Init(src, initialMode)
 	lex.Filename = fname
 	lex.Line = 1
+	lex.line = 1
+	lex.Start = vbscanner.Position{Line: 1, Column: 1}
+	lex.End = lex.Start
+	lex.q = nil
+	lex.held = 0
+	lex.member = false
+	lex.object = ""
+	lex.last = Token{}
+	lex.dates = lex.order
+	lex.errs = nil
+}
+
+// InitFile prepares the lexer for use, choosing how to read the file from the
+// extension of fname: .vbs files are pure VBScript, .hta files are HTML
+// Applications with VBScript in script blocks, and anything else is treated
+// as an ASP page. Windows Script Files (.wsf) are read with package vbwsf.
+func (lex *Lex) InitFile(src io.Reader, fname string) {
+	switch strings.ToLower(filepath.Ext(fname)) {
+	case ".vbs":
+		lex.Init(src, fname, vbscanner.VBS_MODE)
+		lex.SetHTA(false)
+	case ".hta":
+		lex.Init(src, fname, vbscanner.HTML_MODE)
+		lex.SetHTA(true)
+	default:
+		lex.Init(src, fname, vbscanner.HTML_MODE)
+		lex.SetHTA(false)
+	}
+}
+
+// SetHTA turns HTA mode on or off, for reading HTML Applications. It should
+// be called before the first token is read.
+func (lex *Lex) SetHTA(on bool) {
+	lex.s.SetHTA(on)
+}
+
+// SetDottedIdents turns dotted identifier mode on or off. By default names
+// separated by dots are separate tokens, like Server FIELD_SEP CreateObject.
+// In dotted identifier mode they form a single IDENTIFIER, "Server.CreateObject",
+// for compatibility with earlier versions. It should be called before the
+// first token is read.
+func (lex *Lex) SetDottedIdents(on bool) {
+	lex.s.SetDottedIdents(on)
+}
+
+// SetEncoding sets the character encoding of the source. By default it is
+// detected. It should be called before the first token is read.
+func (lex *Lex) SetEncoding(enc vbscanner.Encoding) {
+	lex.s.SetEncoding(enc)
+}
+
+// Encoding returns the character encoding of the source, which is known
+// once the first token has been read.
+func (lex *Lex) Encoding() vbscanner.Encoding {
+	return lex.s.Encoding()
+}
+
+// SetPosition sets the position of the first token, for reading a section of
+// a larger file. It should be called after Init and before the first token is read.
+func (lex *Lex) SetPosition(pos vbscanner.Position) {
+	lex.s.SetPosition(pos)
+	lex.Line = pos.Line
+	lex.line = pos.Line
+	lex.Start = pos
+	lex.End = pos
+}
+
+// SetLossless turns lossless mode on or off. In lossless mode the Leading,
+// Text and Trailing of every token, including the final EOF, concatenate to
+// exactly the input. It should be called before the first token is read.
+func (lex *Lex) SetLossless(on bool) {
+	lex.lossless = on
+	lex.s.SetLossless(on)
 }
 
 // Lex returns the next token in the steam and classifies it. The values returned are
 // the token type, the converted value, and the raw value as a string.
 func (lex *Lex) Lex() (TokenType, interface{}, string) {
-	// handle queue first
-	if len(lex.q) > 0 {
-		return lex.pop()
-	}
+	t := lex.Next()
+	return t.Type, t.Value, t.Raw
+}
 
-	// scan next value
-	tok, value := lex.s.Scan()
+// fill lexes tokens until the queue holds more than n tokens, not counting
+// those held back in compound mode.
+func (lex *Lex) fill(n int) {
+	for len(lex.q)-lex.held <= n {
+		// scan next value
+		tok, value, err := lex.s.Next()
+		start, end := lex.s.Span()
+		leading := lex.s.Leading()
+		if err != nil {
+			lex.report(start, err)
+		}
+
+		if tok == vbscanner.Html {
+			lex.processHTML(value, start, leading)
+			lex.member = false
+			lex.object = ""
+			lex.last = Token{Type: HTML}
+			lex.combine()
+			continue
+		}
+		t, cv, rv := lex.classify(tok, value)
+		if t == ERROR && err == nil {
+			lex.report(start, cv.(error))
+		}
+		lex.member = t == FIELD_SEP
+		lex.object = ""
+		if lex.member && lex.last.Type == INTRINSIC_OBJECT {
+			lex.object = lex.last.Value.(string)
+		}
+		lex.q = append(lex.q, Token{
+			Type:     t,
+			Value:    cv,
+			Raw:      rv,
+			Start:    start,
+			End:      end,
+			Leading:  leading,
+			Text:     lex.s.Text(),
+			Trailing: lex.s.Trailing(),
+			line:     lex.line,
+		})
+		lex.last = lex.q[len(lex.q)-1]
+		lex.combine()
+	}
+}
 
+// classify returns the token type, converted value and raw value for a scanned token.
+func (lex *Lex) classify(tok vbscanner.TokenType, value string) (TokenType, interface{}, string) {
 	// return tok.String(), value
 	if tok == vbscanner.EOF {
 		return EOF, nil, value
@@ -85,116 +224,49 @@ func (lex *Lex) Lex() (TokenType, interface{}, string) {
 
 	switch tok {
 	case vbscanner.Ident:
-		stoken := strings.ToLower(value)
-		switch stoken {
-		// statements
-		case "call", "class", "const", "dim", "do", "loop", "erase", "execute", "executeglobal", "exit", "for", "each", "next", "function", "if", "then", "else", "elseif", "on", "error", "resume", "goto", "option", "explicit", "private", "public", "property", "let", "get", "set", "redim", "randomize", "rem", "select", "case", "stop", "sub", "while", "wend", "with", "end", "raise", "new", "to", "step", "byref", "byval", "is", "in":
-			return STATEMENT, strings.Title(stoken), value
-
-		// functions
-		case "abs", "array", "asc", "atn", "cbool", "cbyte", "ccur", "cdate", "cdbl", "chr", "cint", "clng", "conversions", "cos", "createobject", "csng", "cstr", "date", "dateadd", "datediff", "datepart", "dateserial", "datevalue", "day", "escape", "eval", "exp", "filter", "formatcurrency", "formatdatetime", "formatnumber", "formatpercent", "getlocale", "getobject", "getref", "hex", "hour", "inputbox", "instr", "instrrev", "int", "fix", "isarray", "isdate", "isempty", "isnull", "isnumeric", "isobject", "join", "lbound", "lcase", "left", "len", "loadpicture", "log", "ltrim", "rtrim", "trim", "maths", "mid", "minute", "month", "monthname", "msgbox", "now", "oct", "replace", "rgb", "right", "rnd", "round", "scriptengine", "scriptenginebuildversion", "scriptenginemajorversion", "scriptengineminorversion", "second", "setlocale", "sgn", "sin", "space", "split", "sqr", "strcomp", "string", "strreverse", "tan", "time", "timer", "timeserial", "timevalue", "typename", "ubound", "ucase", "unescape", "vartype", "weekday", "weekdayname", "year":
-			return FUNCTION, formatFunction(stoken), value
-
-		// Keywords
-		case "null", "empty", "nothing":
-			return KEYWORD, strings.Title(stoken), value
-		case "true", "false":
-			b, err := strconv.ParseBool(stoken)
-			if err != nil {
-				panic(err)
+		if lex.member {
+			if lex.object != "" {
+				if sym, ok := Lookup(lex.object + "." + value); ok {
+					return sym.Type, sym.Name[strings.LastIndex(sym.Name, ".")+1:], value
+				}
 			}
-			return KEYWORD_BOOL, b, value
-
-		// constants
-		case "vbblack", "vbred", "vbgreen", "vbyellow", "vbblue", "vbmagenta", "vbcyan", "vbwhite":
-			return COLOR_CONSTANT, formatConstant(stoken), value
-		case "vbbinarycompare", "vbtextcompare":
-			return COMPARE_CONSTANT, formatConstant(stoken), value
-		case "vbsunday", "vbmonday", "vbtuesday", "vbwednesday", "vbthursday", "vbfriday", "vbsaturday", "vbusesystemdayofweek", "vbfirstjan1", "vbfirstfourdays", "vbfirstfullweek":
-			return DATE_CONSTANT, formatConstant(stoken), value
-		case "vbgeneraldate", "vblongdate", "vbshortdate", "vblongtime", "vbshorttime":
-			return DATEFORMAT_CONSTANT, formatConstant(stoken), value
-		case "vbobjecterror":
-			return MISC_CONSTANT, formatConstant(stoken), value
-		case "vbokonly", "vbokcancel", "vbabortretryignore", "vbyesnocancel", "vbyesno", "vbretrycancel", "vbcritical", "vbquestion", "vbexclamation", "vbinformation", "vbdefaultbutton1", "vbdefaultbutton2", "vbdefaultbutton3", "vbdefaultbutton4", "vbapplicationmodal", "vbsystemmodal", "vbok", "vbcancel", "vbabort", "vbretry", "vbignore", "vbyes", "vbno":
-			return MSGBOX_CONSTANT, formatConstant(stoken), value
-		case "vbcr", "vbcrlf", "vbformfeed", "vblf", "vbnewline", "vbnullchar", "vbnullstring", "vbtab", "vbverticaltab":
-			return STRING_CONSTANT, formatConstant(stoken), value
-		case "vbusedefault", "vbtrue", "vbfalse":
-			return TRISTATE_CONSTANT, formatConstant(stoken), value
-		case "vbempty", "vbnull", "vbinteger", "vblong", "vbsingle", "vbdouble", "vbcurrency", "vbdate", "vbstring", "vbobject", "vberror", "vbboolean", "vbvariant", "vbdataobject", "vbdecimal", "vbbyte", "vbarray":
-			return VARTYPE_CONSTANT, formatConstant(stoken), value
-		default:
+			// members may be named like keywords and builtins, as in rs.Close
+			return IDENTIFIER, value, value
+		}
+		sym, ok := Lookup(value)
+		if !ok {
 			return IDENTIFIER, value, value
 		}
+		switch sym.Type {
+		case KEYWORD_BOOL:
+			return KEYWORD_BOOL, strings.EqualFold(sym.Name, "True"), value
+		default:
+			return sym.Type, sym.Name, value
+		}
 
 	// Values
 	case vbscanner.String:
 		return STRING, value, value
-	case vbscanner.Integer:
-		base := 10
-		str := strings.ToLower(value)
-		if strings.HasPrefix(str, "&h") {
-			base = 16
-			str = strings.TrimPrefix(str, "&h")
-		} else if strings.HasPrefix(strings.ToLower(str), "&o") {
-			base = 8
-			str = strings.TrimPrefix(str, "&o")
+	case vbscanner.Integer, vbscanner.Float:
+		parse := parseInteger
+		if tok == vbscanner.Float {
+			parse = parseFloat
 		}
-		i, err := strconv.ParseInt(str, base, 64)
+		t, v, err := parse(value)
 		if err != nil {
-			panic(err)
+			return ERROR, err, value
 		}
-		return INT, i, value
-	case vbscanner.Float:
-		f, err := strconv.ParseFloat(value, 64)
-		if err != nil {
-			panic(err)
-		}
-		return FLOAT, f, value
+		return t, v, value
 	case vbscanner.Date:
-		formats := []string{
-			"02-Jan-2006 15:04:05",  // #31-Dec-1999 21:26:00#
-			"02-JAN-2006 15:04:05",  // #31-DEC-1999 21:26:00#
-			"2006-01-02 15:04:05",   // #1999-12-31 21:26:00#
-			"01/02/2006 3:04:05 PM", // #12/31/1999 9:26:00 PM#
-			"01/02/2006 3:04:05 pm", // #12/31/1999 9:26:00 pm#
-			"1/2/2006 3:04:05 PM",   // #12/31/1999 9:26:00 PM#
-			"1/2/2006 3:04:05 pm",   // #12/31/1999 9:26:00 pm#
-			"02-Jan-2006",           // #31-Dec-1999#
-			"02-JAN-2006",           // #31-DEC-1999#
-			"2006-01-02",            // #1999-12-31#
-			"01/02/2006",            // #12/31/1999#
-			"1/2/2006",              // #12/31/1999#
-			"01-02-2006",            // #12-31-1999#
-			"1-2-2006",              // #12-31-1999#
-			"15:04:05",              // #21:26:00#
-			"03:04:05 PM",           // #21:26:00#
-			"3:04:05 PM",            // #21:26:00#
-		}
-		loc, err := time.LoadLocation("America/New_York")
+		t, err := parseDate(value, lex.dates, lex.location())
 		if err != nil {
-			loc = time.Local
+			return ERROR, fmt.Errorf("%w %q: %w", ErrInvalidDate, value, err), value
 		}
-		for _, fmt := range formats {
-			t, err := time.ParseInLocation(fmt, value, loc)
-			if err == nil {
-				return DATE, t, value
-			}
-		}
-		panic(fmt.Errorf("invalid date literal %q", value))
-		// return DATE, value, value
+		return DATE, t, value
 	case vbscanner.Comment:
 		return COMMENT, value, value
-	case vbscanner.Html:
-		//lex.Line += strings.Count(value, "\n")
-		//return HTML, value, value
-		lex.processHTML(value)
-		return lex.pop()
 	case vbscanner.Char:
 		switch value {
-		case "_":
-			return CONTINUATION, value, value
 		case ",":
 			return LIST_SEP, value, value
 		case "(":
@@ -207,65 +279,67 @@ func (lex *Lex) Lex() (TokenType, interface{}, string) {
 		return CHAR, value, value
 	case vbscanner.EOL:
 		if value != ":" {
-			lex.Line++
+			lex.line++
+			return EOL, "\n", value
 		}
 		return EOL, value, value
+	case vbscanner.Continuation:
+		return CONTINUATION, value, value
 	case vbscanner.Op:
 		return OP, strings.Title(value), value
+	case vbscanner.Output:
+		return OUTPUT, value, value
+	case vbscanner.Directive:
+		attrs := ParseDirective(value)
+		if lcid, err := strconv.Atoi(attrs["LCID"]); err == nil {
+			lex.dates = lcidOrder(lcid)
+		}
+		return DIRECTIVE, attrs, value
+	case vbscanner.ScriptTag:
+		return SCRIPT_TAG, value, value
 	}
 
 	panic("How did we get here?")
 	// return "IDENT", value
 }
 
-// push puts an item on the queue to be returned in subsequent calls to Lex.
-// lineIncr is the number of lines to add.
-func (lex *Lex) push(t TokenType, cv interface{}, rv string, lineIncr int) {
-	lex.q = append(lex.q, qitem{
-		T:        t,
-		CV:       cv,
-		RV:       rv,
-		LineIncr: lineIncr,
-	})
-}
-
-// pop returns a stored value from the queue to be returned by Lex, and increments
-// the line number accordingly.
-func (lex *Lex) pop() (TokenType, interface{}, string) {
-	if len(lex.q) == 0 {
-		panic("empty queue")
+// push puts a token on the queue to be returned in subsequent calls to Next.
+// lineIncr is the number of lines the token spans.
+func (lex *Lex) push(t Token, lineIncr int) {
+	if !lex.lossless {
+		t.Text = ""
 	}
-	itm := lex.q[0]
-	copy(lex.q, lex.q[1:])
-	lex.q = lex.q[0 : len(lex.q)-1]
-	lex.Line += itm.LineIncr
-	return itm.T, itm.CV, itm.RV
-}
-
-// qitem is the data stored on the queue
-type qitem struct {
-	T        TokenType   // Token type
-	CV       interface{} // Converted value
-	RV       string      // Raw value
-	LineIncr int         // Line increment
+	lex.line += lineIncr
+	t.line = lex.line
+	lex.q = append(lex.q, t)
 }
 
 var re = regexp.MustCompile(`<!--\s*#include\s+(file|virtual)\s*=\s*"([ \w/.\\\-]+)"\s*-->`)
 
 // processHTML looks for embedded script includes and builds up the
-// queue as needed.
-func (lex *Lex) processHTML(html string) {
-	fragments := re.Split(html, -1)
-	submatches := re.FindAllStringSubmatch(html, -1)
-	for i, frag := range fragments {
-		lex.push(HTML, frag, frag, strings.Count(frag, "\n"))
-		if i < len(submatches) {
-			submatch := submatches[i]
-			if submatch[1] == "file" {
-				lex.push(FILE_INCLUDE, submatch[2], submatch[2], strings.Count(submatch[0], "\n"))
-			} else {
-				lex.push(VIRTUAL_INCLUDE, submatch[2], submatch[2], strings.Count(submatch[0], "\n"))
-			}
+// queue as needed. start is the position of the beginning of the HTML,
+// and leading is the text preceding it.
+func (lex *Lex) processHTML(html string, start vbscanner.Position, leading string) {
+	matches := re.FindAllStringSubmatchIndex(html, -1)
+	prev := 0
+	pos := start
+	for _, m := range matches {
+		frag := html[prev:m[0]]
+		end := pos.Advance(frag)
+		lex.push(Token{Type: HTML, Value: frag, Raw: frag, Start: pos, End: end, Leading: leading, Text: frag}, end.Line-pos.Line)
+		leading = ""
+		directive := html[m[0]:m[1]]
+		kind, name := html[m[2]:m[3]], html[m[4]:m[5]]
+		pos, end = end, end.Advance(directive)
+		if kind == "file" {
+			lex.push(Token{Type: FILE_INCLUDE, Value: name, Raw: name, Start: pos, End: end, Text: directive}, end.Line-pos.Line)
+		} else {
+			lex.push(Token{Type: VIRTUAL_INCLUDE, Value: name, Raw: name, Start: pos, End: end, Text: directive}, end.Line-pos.Line)
 		}
+		pos = end
+		prev = m[1]
 	}
+	frag := html[prev:]
+	end := pos.Advance(frag)
+	lex.push(Token{Type: HTML, Value: frag, Raw: frag, Start: pos, End: end, Leading: leading, Text: frag}, end.Line-pos.Line)
 }
@@ -0,0 +1,165 @@
+package vblexer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// lexString returns the tokens read from src, up to EOF, after calling
+// setup if given.
+func lexString(src string, mode vbscanner.Mode, setup func(*Lex)) []Token {
+	var lex Lex
+	lex.Init(strings.NewReader(src), "test.asp", mode)
+	if setup != nil {
+		setup(&lex)
+	}
+	var list []Token
+	for tok := range lex.All() {
+		list = append(list, tok)
+	}
+	return list
+}
+
+// describe returns the types and raw values of list, like "IDENTIFIER x".
+func describe(list []Token) string {
+	s := make([]string, len(list))
+	for i, t := range list {
+		s[i] = fmt.Sprintf("%v %s", t.Type, t.Raw)
+	}
+	return strings.Join(s, ", ")
+}
+
+func TestValues(t *testing.T) {
+	tests := []struct {
+		in   string
+		typ  TokenType
+		want interface{}
+	}{
+		{"1", INT, int16(1)},
+		{"100000", INT, int32(100000)},
+		{"&HFF", INT, int16(255)},
+		{"3000000000", FLOAT, 3e9},
+		{"1.5", FLOAT, 1.5},
+		{`"a""b"`, STRING, `a"b`},
+		{"x", IDENTIFIER, "x"},
+		{"True", KEYWORD_BOOL, true},
+		{"false", KEYWORD_BOOL, false},
+		{"' note", COMMENT, " note"},
+		{"(", PAREN_OPEN, "("},
+		{",", LIST_SEP, ","},
+		{"<>", OP, "<>"},
+	}
+	for _, tt := range tests {
+		list := lexString(tt.in, vbscanner.VBS_MODE, nil)
+		if len(list) != 1 || list[0].Type != tt.typ || list[0].Value != tt.want {
+			t.Errorf("%q: got %s, want %v %#v", tt.in, describe(list), tt.typ, tt.want)
+		}
+	}
+	list := lexString("#1/2/2003#", vbscanner.VBS_MODE, func(lex *Lex) { lex.SetLocation(time.UTC) })
+	if len(list) != 1 || list[0].Value != time.Date(2003, 1, 2, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("date: got %s", describe(list))
+	}
+}
+
+func TestNextPeek(t *testing.T) {
+	var lex Lex
+	lex.Init(strings.NewReader("x = 1\ny"), "test.vbs", vbscanner.VBS_MODE)
+	if p := lex.Peek(2); p.Raw != "1" {
+		t.Errorf("Peek(2) = %q, want 1", p.Raw)
+	}
+	if p, n := lex.Peek(0), lex.Next(); p != n || n.Raw != "x" {
+		t.Errorf("Peek(0) = %q, Next = %q, want x", p.Raw, n.Raw)
+	}
+	var rest []Token
+	for tok := range lex.All() {
+		rest = append(rest, tok)
+		if tok.Type == EOL {
+			break
+		}
+	}
+	if got := describe(rest); got != "OP =, INT 1, EOL \n" {
+		t.Errorf("All: got %s", got)
+	}
+	if n := lex.Next(); n.Raw != "y" || lex.Line != 2 {
+		t.Errorf("Next after All = %q on line %d, want y on line 2", n.Raw, lex.Line)
+	}
+	for i := 0; i < 2; i++ {
+		if n := lex.Next(); n.Type != EOF {
+			t.Errorf("Next at end = %v, want EOF", n.Type)
+		}
+	}
+}
+
+func TestPositions(t *testing.T) {
+	list := lexString("<p>\n<% x = \"é\" %>\n<%= y %>", vbscanner.HTML_MODE, nil)
+	want := []struct {
+		raw        string
+		start, end vbscanner.Position
+	}{
+		{"<p>\n", vbscanner.Position{Offset: 0, Line: 1, Column: 1}, vbscanner.Position{Offset: 4, Line: 2, Column: 1}},
+		{"x", vbscanner.Position{Offset: 7, Line: 2, Column: 4}, vbscanner.Position{Offset: 8, Line: 2, Column: 5}},
+		{"=", vbscanner.Position{Offset: 9, Line: 2, Column: 6}, vbscanner.Position{Offset: 10, Line: 2, Column: 7}},
+		{"é", vbscanner.Position{Offset: 11, Line: 2, Column: 8}, vbscanner.Position{Offset: 15, Line: 2, Column: 11}},
+		{"\n", vbscanner.Position{Offset: 18, Line: 2, Column: 14}, vbscanner.Position{Offset: 19, Line: 3, Column: 1}},
+		{"=", vbscanner.Position{Offset: 21, Line: 3, Column: 3}, vbscanner.Position{Offset: 22, Line: 3, Column: 4}},
+		{"y", vbscanner.Position{Offset: 23, Line: 3, Column: 5}, vbscanner.Position{Offset: 24, Line: 3, Column: 6}},
+		{"", vbscanner.Position{Offset: 27, Line: 3, Column: 9}, vbscanner.Position{Offset: 27, Line: 3, Column: 9}},
+	}
+	if len(list) != len(want) {
+		t.Fatalf("got %s", describe(list))
+	}
+	for i, w := range want {
+		tok := list[i]
+		if tok.Raw != w.raw || tok.Start != w.start || tok.End != w.end {
+			t.Errorf("token %d: got %q at %+v-%+v, want %q at %+v-%+v", i, tok.Raw, tok.Start, tok.End, w.raw, w.start, w.end)
+		}
+	}
+}
+
+func TestMembers(t *testing.T) {
+	const src = "Set f = fso.GetFile(p).ParentFolder\nWith rs\n.MoveNext\nEnd With"
+	tests := []struct {
+		dotted bool
+		want   string
+	}{
+		{false, "STATEMENT Set, IDENTIFIER f, OP =, IDENTIFIER fso, FIELD_SEP ., IDENTIFIER GetFile, PAREN_OPEN (, IDENTIFIER p, PAREN_CLOSE ), FIELD_SEP ., IDENTIFIER ParentFolder, EOL \n, " +
+			"STATEMENT With, IDENTIFIER rs, EOL \n, FIELD_SEP ., IDENTIFIER MoveNext, EOL \n, STATEMENT End, STATEMENT With"},
+		{true, "STATEMENT Set, IDENTIFIER f, OP =, IDENTIFIER fso.GetFile, PAREN_OPEN (, IDENTIFIER p, PAREN_CLOSE ), FIELD_SEP ., IDENTIFIER ParentFolder, EOL \n, " +
+			"STATEMENT With, IDENTIFIER rs, EOL \n, FIELD_SEP ., IDENTIFIER MoveNext, EOL \n, STATEMENT End, STATEMENT With"},
+	}
+	for _, tt := range tests {
+		list := lexString(src, vbscanner.VBS_MODE, func(lex *Lex) { lex.SetDottedIdents(tt.dotted) })
+		if got := describe(list); got != tt.want {
+			t.Errorf("dotted %v: got %s\nwant %s", tt.dotted, got, tt.want)
+		}
+	}
+}
+
+func TestIntrinsics(t *testing.T) {
+	const src = "response.write err.number & adopenstatic & forreading & Server.MapPath(x) & conn.Execute"
+	tests := []struct {
+		dotted bool
+		want   string
+	}{
+		{false, "INTRINSIC_OBJECT Response, FIELD_SEP ., INTRINSIC_MEMBER Write, INTRINSIC_OBJECT Err, FIELD_SEP ., INTRINSIC_MEMBER Number, OP &, ADO_CONSTANT adOpenStatic, OP &, FSO_CONSTANT ForReading, OP &, " +
+			"INTRINSIC_OBJECT Server, FIELD_SEP ., INTRINSIC_MEMBER MapPath, PAREN_OPEN (, IDENTIFIER x, PAREN_CLOSE ), OP &, IDENTIFIER conn, FIELD_SEP ., IDENTIFIER Execute"},
+		{true, "INTRINSIC_MEMBER Response.Write, INTRINSIC_MEMBER Err.Number, OP &, ADO_CONSTANT adOpenStatic, OP &, FSO_CONSTANT ForReading, OP &, " +
+			"INTRINSIC_MEMBER Server.MapPath, PAREN_OPEN (, IDENTIFIER x, PAREN_CLOSE ), OP &, IDENTIFIER conn.Execute"},
+	}
+	for _, tt := range tests {
+		list := lexString(src, vbscanner.VBS_MODE, func(lex *Lex) { lex.SetDottedIdents(tt.dotted) })
+		// the values have the canonical spelling
+		for i := range list {
+			if s, ok := list[i].Value.(string); ok {
+				list[i].Raw = s
+			}
+		}
+		if got := describe(list); got != tt.want {
+			t.Errorf("dotted %v: got %s\nwant %s", tt.dotted, got, tt.want)
+		}
+	}
+}
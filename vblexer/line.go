@@ -0,0 +1,61 @@
+package vblexer
+
+import (
+	"iter"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// Line is a logical line of code: one or more physical lines joined by
+// line continuations. The tokens keep their physical positions.
+type Line struct {
+	Tokens []Token            // tokens on the line, without the continuations and the line ends after them
+	Start  vbscanner.Position // start of the first token
+	End    vbscanner.Position // end of the last token
+}
+
+// Lines returns an iterator over the remaining logical lines in the stream.
+// A line ends after an EOL token that ends a physical line, and HTML,
+// include and script tag tokens each form a line of their own. Statements
+// separated by ":" stay on the same line. The iterator consumes tokens as
+// Next does.
+func (lex *Lex) Lines() iter.Seq[Line] {
+	return func(yield func(Line) bool) {
+		var line Line
+		flush := func() bool {
+			if len(line.Tokens) == 0 {
+				return true
+			}
+			line.Start = line.Tokens[0].Start
+			line.End = line.Tokens[len(line.Tokens)-1].End
+			ok := yield(line)
+			line = Line{}
+			return ok
+		}
+		for {
+			t := lex.Next()
+			switch t.Type {
+			case EOF:
+				flush()
+				return
+			case CONTINUATION:
+				if n := lex.Peek(0); n.Type == EOL && n.Value == "\n" {
+					lex.Next()
+				}
+			case HTML, FILE_INCLUDE, VIRTUAL_INCLUDE, SCRIPT_TAG:
+				if !flush() {
+					return
+				}
+				line.Tokens = append(line.Tokens, t)
+				if !flush() {
+					return
+				}
+			default:
+				line.Tokens = append(line.Tokens, t)
+				if t.Type == EOL && t.Value == "\n" && !flush() {
+					return
+				}
+			}
+		}
+	}
+}
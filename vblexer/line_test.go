@@ -0,0 +1,69 @@
+package vblexer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+func TestLineEnds(t *testing.T) {
+	for _, eol := range []string{"\n", "\r\n", "\r"} {
+		list := lexString("x = 1"+eol+"y = 2 ' note"+eol, vbscanner.VBS_MODE, nil)
+		var ends []Token
+		for _, tok := range list {
+			switch tok.Type {
+			case EOL:
+				ends = append(ends, tok)
+			case COMMENT:
+				if tok.Value != " note" {
+					t.Errorf("%q: comment %q", eol, tok.Value)
+				}
+			}
+		}
+		if len(ends) != 2 {
+			t.Errorf("%q: got %s", eol, describe(list))
+			continue
+		}
+		for _, tok := range ends {
+			if tok.Value != "\n" || tok.Raw != eol {
+				t.Errorf("%q: EOL with value %q and raw %q", eol, tok.Value, tok.Raw)
+			}
+		}
+		if ends[1].Start.Line != 2 || ends[1].End.Line != 3 {
+			t.Errorf("%q: second EOL at %v-%v", eol, ends[1].Start, ends[1].End)
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	var lex Lex
+	lex.Init(strings.NewReader("<p>\n<% x = 1 + _\r\n  2 : y = 3\nz _\n= 4 %>"), "test.asp", vbscanner.HTML_MODE)
+	tests := []struct {
+		tokens     string
+		start, end int // lines of the start and end of the line
+	}{
+		{"<p>\n", 1, 2},
+		{"x = 1 + 2 : y = 3 \n", 2, 4},
+		{"z = 4", 4, 5},
+		{"", 5, 5},
+	}
+	var i int
+	for line := range lex.Lines() {
+		if i == len(tests) {
+			t.Fatalf("line %d: more lines than expected", i)
+		}
+		raw := make([]string, len(line.Tokens))
+		for j, tok := range line.Tokens {
+			raw[j] = tok.Raw
+		}
+		tt := tests[i]
+		if got := strings.Join(raw, " "); got != tt.tokens || line.Start.Line != tt.start || line.End.Line != tt.end {
+			t.Errorf("line %d: got %q at lines %d-%d, want %q at %d-%d", i, got, line.Start.Line, line.End.Line, tt.tokens, tt.start, tt.end)
+		}
+		i++
+	}
+	if i != len(tests) {
+		t.Errorf("got %d lines, want %d", i, len(tests))
+	}
+}
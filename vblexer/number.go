@@ -0,0 +1,65 @@
+package vblexer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parseInteger converts an integer literal to the value VBScript gives it.
+// Decimal numbers are an Integer (int16) up to 32767, a Long (int32) up to
+// 2147483647, and a Double (float64) beyond that, which makes the token a
+// FLOAT. Hexadecimal and octal numbers are an Integer up to &HFFFF and a
+// Long up to &HFFFFFFFF, keeping the bits, so &HFFFF is -1 and &HFFFF& is
+// 65535. Larger ones overflow.
+func parseInteger(value string) (TokenType, interface{}, error) {
+	str := strings.ToLower(value)
+	base := 10
+	if strings.HasPrefix(str, "&h") {
+		base = 16
+	} else if strings.HasPrefix(str, "&o") {
+		base = 8
+	}
+	if base == 10 {
+		i, err := strconv.ParseUint(str, 10, 64)
+		switch {
+		case err != nil || i > math.MaxInt32:
+			return parseFloat(value)
+		case i > math.MaxInt16:
+			return INT, int32(i), nil
+		default:
+			return INT, int16(i), nil
+		}
+	}
+
+	long := strings.HasSuffix(str, "&")
+	str = strings.TrimSuffix(str[2:], "&")
+	i, err := strconv.ParseUint(str, base, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return INT, nil, fmt.Errorf("invalid number %q", value)
+	}
+	switch {
+	case err != nil || i > math.MaxUint32:
+		return INT, nil, fmt.Errorf("overflow in %q", value)
+	case long || i > math.MaxUint16:
+		return INT, int32(uint32(i)), nil
+	default:
+		return INT, int16(uint16(i)), nil
+	}
+}
+
+// parseFloat converts a float literal to a Double (float64). Numbers too
+// large for a Double overflow.
+func parseFloat(value string) (TokenType, interface{}, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	switch {
+	case err != nil && !errors.Is(err, strconv.ErrRange):
+		return FLOAT, nil, fmt.Errorf("invalid number %q", value)
+	case math.IsInf(f, 0):
+		return FLOAT, nil, fmt.Errorf("overflow in %q", value)
+	}
+	// numbers too small for a Double are zero
+	return FLOAT, f, nil
+}
@@ -0,0 +1,68 @@
+package vblexer
+
+import (
+	"testing"
+)
+
+func TestParseInteger(t *testing.T) {
+	tests := []struct {
+		in    string
+		typ   TokenType
+		value interface{}
+		err   bool
+	}{
+		{"0", INT, int16(0), false},
+		{"32767", INT, int16(32767), false},
+		{"32768", INT, int32(32768), false},
+		{"2147483647", INT, int32(2147483647), false},
+		{"2147483648", FLOAT, float64(2147483648), false},
+		{"99999999999999999999", FLOAT, float64(1e20), false},
+		{"&H7FFF", INT, int16(32767), false},
+		{"&H8000", INT, int16(-32768), false},
+		{"&HFFFF", INT, int16(-1), false},
+		{"&hffff", INT, int16(-1), false},
+		{"&H8000&", INT, int32(32768), false},
+		{"&HFFFF&", INT, int32(65535), false},
+		{"&H10000", INT, int32(65536), false},
+		{"&HFFFFFFFF", INT, int32(-1), false},
+		{"&H100000000", INT, nil, true},
+		{"&O10", INT, int16(8), false},
+		{"&O177777", INT, int16(-1), false},
+		{"&O177777&", INT, int32(65535), false},
+	}
+	for _, tt := range tests {
+		typ, v, err := parseInteger(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("parseInteger(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if typ != tt.typ || v != tt.value {
+			t.Errorf("parseInteger(%q) = %v, %#v; want %v, %#v", tt.in, typ, v, tt.typ, tt.value)
+		}
+	}
+}
+
+func TestParseFloat(t *testing.T) {
+	tests := []struct {
+		in    string
+		value interface{}
+		err   bool
+	}{
+		{"1.5", 1.5, false},
+		{".5", 0.5, false},
+		{"1.", 1.0, false},
+		{"1E3", 1000.0, false},
+		{"1e-400", 0.0, false},
+		{"1e400", nil, true},
+	}
+	for _, tt := range tests {
+		typ, v, err := parseFloat(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("parseFloat(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if typ != FLOAT || v != tt.value {
+			t.Errorf("parseFloat(%q) = %v, %#v; want FLOAT, %#v", tt.in, typ, v, tt.value)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package vblexer
+
+import (
+	"iter"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// Token is a classified token read by the lexer.
+type Token struct {
+	Type     TokenType          // Token type
+	Value    interface{}        // Converted value
+	Raw      string             // Raw value
+	Start    vbscanner.Position // Start of the token
+	End      vbscanner.Position // End of the token
+	Leading  string             // Whitespace preceding the token
+	Text     string             // Original spelling, in lossless mode
+	Trailing string             // Whitespace following the token, in lossless mode
+	line     int                // Line number after the token, for Lex.Line
+}
+
+// Next returns the next token in the stream. Once the end of the stream is
+// reached, it returns EOF tokens.
+func (lex *Lex) Next() Token {
+	lex.fill(0)
+	t := lex.q[0]
+	copy(lex.q, lex.q[1:])
+	lex.q = lex.q[0 : len(lex.q)-1]
+	lex.Line = t.line
+	lex.Start, lex.End = t.Start, t.End
+	return t
+}
+
+// Peek returns the token n positions ahead without consuming it.
+// Peek(0) returns the token that the next call to Next will return.
+func (lex *Lex) Peek(n int) Token {
+	lex.fill(n)
+	return lex.q[n]
+}
+
+// All returns an iterator over the remaining tokens in the stream, stopping
+// at EOF. The iterator consumes tokens as Next does, so Next and Peek may be
+// used within the loop.
+func (lex *Lex) All() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for {
+			t := lex.Next()
+			if t.Type == EOF || !yield(t) {
+				return
+			}
+		}
+	}
+}
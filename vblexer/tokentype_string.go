@@ -39,11 +39,20 @@ func _() {
 	_ = x[PAREN_OPEN-28]
 	_ = x[PAREN_CLOSE-29]
 	_ = x[FIELD_SEP-30]
+	_ = x[OUTPUT-31]
+	_ = x[DIRECTIVE-32]
+	_ = x[SCRIPT_TAG-33]
+	_ = x[INTRINSIC_OBJECT-34]
+	_ = x[INTRINSIC_MEMBER-35]
+	_ = x[ADO_CONSTANT-36]
+	_ = x[FSO_CONSTANT-37]
+	_ = x[COMPOUND-38]
+	_ = x[ERROR-39]
 }
 
-const _TokenType_name = "EOFSTATEMENTFUNCTIONKEYWORDKEYWORD_BOOLCOLOR_CONSTANTCOMPARE_CONSTANTDATE_CONSTANTDATEFORMAT_CONSTANTMISC_CONSTANTMSGBOX_CONSTANTSTRING_CONSTANTTRISTATE_CONSTANTVARTYPE_CONSTANTIDENTIFIERSTRINGINTFLOATDATECOMMENTHTMLCHAREOLOPCONTINUATIONFILE_INCLUDEVIRTUAL_INCLUDELIST_SEPPAREN_OPENPAREN_CLOSEFIELD_SEP"
+const _TokenType_name = "EOFSTATEMENTFUNCTIONKEYWORDKEYWORD_BOOLCOLOR_CONSTANTCOMPARE_CONSTANTDATE_CONSTANTDATEFORMAT_CONSTANTMISC_CONSTANTMSGBOX_CONSTANTSTRING_CONSTANTTRISTATE_CONSTANTVARTYPE_CONSTANTIDENTIFIERSTRINGINTFLOATDATECOMMENTHTMLCHAREOLOPCONTINUATIONFILE_INCLUDEVIRTUAL_INCLUDELIST_SEPPAREN_OPENPAREN_CLOSEFIELD_SEPOUTPUTDIRECTIVESCRIPT_TAGINTRINSIC_OBJECTINTRINSIC_MEMBERADO_CONSTANTFSO_CONSTANTCOMPOUNDERROR"
 
-var _TokenType_index = [...]uint16{0, 3, 12, 20, 27, 39, 53, 69, 82, 101, 114, 129, 144, 161, 177, 187, 193, 196, 201, 205, 212, 216, 220, 223, 225, 237, 249, 264, 272, 282, 293, 302}
+var _TokenType_index = [...]uint16{0, 3, 12, 20, 27, 39, 53, 69, 82, 101, 114, 129, 144, 161, 177, 187, 193, 196, 201, 205, 212, 216, 220, 223, 225, 237, 249, 264, 272, 282, 293, 302, 308, 317, 327, 343, 359, 371, 383, 391, 396}
 
 func (i TokenType) String() string {
 	if i < 0 || i >= TokenType(len(_TokenType_index)-1) {
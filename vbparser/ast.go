@@ -0,0 +1,421 @@
+package vbparser
+
+import (
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// Node is implemented by all nodes in the syntax tree.
+type Node interface {
+	Pos() vbscanner.Position // position of the first character of the node
+	End() vbscanner.Position // position just past the last character of the node
+}
+
+// Stmt is implemented by all statement nodes.
+type Stmt interface {
+	Node
+	stmtNode()
+}
+
+// Expr is implemented by all expression nodes.
+type Expr interface {
+	Node
+	exprNode()
+}
+
+// Span is the range of source covered by a node.
+type Span struct {
+	StartPos vbscanner.Position
+	EndPos   vbscanner.Position
+}
+
+// Pos returns the start of the span.
+func (s Span) Pos() vbscanner.Position { return s.StartPos }
+
+// End returns the end of the span.
+func (s Span) End() vbscanner.Position { return s.EndPos }
+
+// Access describes the visibility given to a declaration.
+type Access int
+
+// Access types
+const (
+	AccessNone    Access = iota // no access modifier given
+	AccessPublic                // Public
+	AccessPrivate               // Private
+)
+
+// ProcKind describes the kind of procedure being declared.
+type ProcKind int
+
+// Procedure kinds
+const (
+	SubProc         ProcKind = iota // Sub
+	FunctionProc                    // Function
+	PropertyGetProc                 // Property Get
+	PropertyLetProc                 // Property Let
+	PropertySetProc                 // Property Set
+)
+
+// LoopKind describes the condition of a Do loop.
+type LoopKind int
+
+// Loop kinds
+const (
+	LoopForever LoopKind = iota // no condition
+	LoopWhile                   // While condition
+	LoopUntil                   // Until condition
+)
+
+// File is a parsed ASP page or VBScript file.
+type File struct {
+	Span
+	Name     string           // file name given to the parser
+	Body     []Stmt           // statements, HTML and includes in source order
+	Comments []*vblexer.Token // comments, in source order
+}
+
+// ----------------------------------------------------------------------------
+// Expressions
+
+// Ident is a name, such as a variable or procedure name.
+type Ident struct {
+	Span
+	Name string
+}
+
+// BasicLit is a literal value. Kind is one of the vblexer token types STRING,
+// INT, FLOAT, DATE, KEYWORD or KEYWORD_BOOL, and Value holds the value that the
+// lexer converted it to.
+type BasicLit struct {
+	Span
+	Kind  vblexer.TokenType
+	Value interface{}
+	Raw   string
+}
+
+// ParenExpr is an expression in parentheses.
+type ParenExpr struct {
+	Span
+	X Expr
+}
+
+// UnaryExpr is a unary operation. Op is "-", "+" or "Not".
+type UnaryExpr struct {
+	Span
+	Op string
+	X  Expr
+}
+
+// BinaryExpr is a binary operation. Op is the operator as classified by the
+// lexer, such as "+", "<>", "Mod" or "And", or "Is".
+type BinaryExpr struct {
+	Span
+	Op string
+	X  Expr
+	Y  Expr
+}
+
+// MemberExpr selects a member of an object, like X.Name. X is nil for
+// members of the object of a With block, like .Name.
+type MemberExpr struct {
+	Span
+	X    Expr
+	Name *Ident
+}
+
+// CallOrIndexExpr is a name followed by parenthesized arguments. VBScript does not
+// distinguish calling a function from indexing an array or collection.
+type CallOrIndexExpr struct {
+	Span
+	Fun  Expr
+	Args []Expr
+}
+
+// NewExpr creates an instance of a class.
+type NewExpr struct {
+	Span
+	Class *Ident
+}
+
+// OmittedExpr stands in for an argument that was left out, like the second
+// argument in rs.Open sql, , adOpenStatic.
+type OmittedExpr struct {
+	Span
+}
+
+// BadExpr stands in for a literal that could not be read, like an invalid
+// date literal. The lexer reports the problem.
+type BadExpr struct {
+	Span
+}
+
+func (*Ident) exprNode()           {}
+func (*BasicLit) exprNode()        {}
+func (*ParenExpr) exprNode()       {}
+func (*UnaryExpr) exprNode()       {}
+func (*BinaryExpr) exprNode()      {}
+func (*MemberExpr) exprNode()      {}
+func (*CallOrIndexExpr) exprNode() {}
+func (*NewExpr) exprNode()         {}
+func (*OmittedExpr) exprNode()     {}
+func (*BadExpr) exprNode()         {}
+
+// ----------------------------------------------------------------------------
+// Page structure
+
+// HTMLStmt is a block of HTML outside of script delimiters.
+type HTMLStmt struct {
+	Span
+	Text string
+}
+
+// IncludeStmt is an #include directive.
+type IncludeStmt struct {
+	Span
+	Virtual bool   // true for virtual=, false for file=
+	Path    string // path given in the directive
+}
+
+// OutputStmt is the expression in an output block, like <%= expr %>.
+type OutputStmt struct {
+	Span
+	Value Expr
+}
+
+// DirectiveStmt is a page directive, like <%@ Language=VBScript %>.
+type DirectiveStmt struct {
+	Span
+	Attrs map[string]string // attributes, as parsed by vblexer.ParseDirective
+}
+
+// ----------------------------------------------------------------------------
+// Declarations
+
+// VarDecl declares a single variable, optionally as an array.
+type VarDecl struct {
+	Span
+	Name   *Ident
+	Array  bool   // declared with parentheses
+	Bounds []Expr // array bounds, empty for dynamic arrays
+}
+
+// DimStmt declares variables with Dim, or with Public or Private.
+type DimStmt struct {
+	Span
+	Access Access
+	Vars   []*VarDecl
+}
+
+// ReDimStmt resizes dynamic arrays.
+type ReDimStmt struct {
+	Span
+	Preserve bool
+	Vars     []*VarDecl
+}
+
+// ConstDecl declares a single constant.
+type ConstDecl struct {
+	Span
+	Name  *Ident
+	Value Expr
+}
+
+// ConstStmt declares constants.
+type ConstStmt struct {
+	Span
+	Access Access
+	Consts []*ConstDecl
+}
+
+// Param is a parameter of a procedure.
+type Param struct {
+	Span
+	ByVal bool // declared ByVal; parameters are ByRef by default
+	ByRef bool // declared ByRef explicitly
+	Name  *Ident
+	Array bool // declared with parentheses
+}
+
+// ProcDecl declares a Sub, Function or Property.
+type ProcDecl struct {
+	Span
+	Access  Access
+	Default bool // declared Public Default
+	Kind    ProcKind
+	Name    *Ident
+	Params  []*Param
+	Body    []Stmt
+}
+
+// ClassDecl declares a class.
+type ClassDecl struct {
+	Span
+	Name    *Ident
+	Members []Stmt
+}
+
+// ----------------------------------------------------------------------------
+// Statements
+
+// AssignStmt assigns a value, optionally with Set.
+type AssignStmt struct {
+	Span
+	Set    bool
+	Target Expr
+	Value  Expr
+}
+
+// CallStmt invokes a procedure, optionally with Call.
+type CallStmt struct {
+	Span
+	Call   bool // used the Call keyword
+	Callee Expr
+	Args   []Expr
+}
+
+// IfStmt is an If statement, on a single line or as a block.
+type IfStmt struct {
+	Span
+	SingleLine bool
+	Cond       Expr
+	Then       []Stmt
+	ElseIfs    []*ElseIfClause
+	Else       []Stmt
+}
+
+// ElseIfClause is an ElseIf part of an If statement.
+type ElseIfClause struct {
+	Span
+	Cond Expr
+	Body []Stmt
+}
+
+// SelectStmt is a Select Case statement.
+type SelectStmt struct {
+	Span
+	Value Expr
+	Cases []*CaseClause
+}
+
+// CaseClause is a Case within a Select Case statement.
+type CaseClause struct {
+	Span
+	Else   bool   // Case Else
+	Values []Expr // values to match
+	Body   []Stmt
+}
+
+// ForStmt is a For ... Next loop.
+type ForStmt struct {
+	Span
+	Var  *Ident
+	From Expr
+	To   Expr
+	Step Expr // nil if not given
+	Body []Stmt
+}
+
+// ForEachStmt is a For Each ... Next loop.
+type ForEachStmt struct {
+	Span
+	Var  *Ident
+	In   Expr
+	Body []Stmt
+}
+
+// DoStmt is a Do ... Loop statement.
+type DoStmt struct {
+	Span
+	Kind      LoopKind
+	CondAtEnd bool // condition given after Loop rather than after Do
+	Cond      Expr // nil for LoopForever
+	Body      []Stmt
+}
+
+// WhileStmt is a While ... Wend loop.
+type WhileStmt struct {
+	Span
+	Cond Expr
+	Body []Stmt
+}
+
+// WithStmt is a With block.
+type WithStmt struct {
+	Span
+	Object Expr
+	Body   []Stmt
+}
+
+// OnErrorStmt is On Error Resume Next or On Error GoTo 0.
+type OnErrorStmt struct {
+	Span
+	ResumeNext bool // false for GoTo 0
+}
+
+// ExitStmt leaves a loop or procedure.
+type ExitStmt struct {
+	Span
+	Kind string // Do, For, Function, Sub or Property
+}
+
+// OptionExplicitStmt is Option Explicit.
+type OptionExplicitStmt struct {
+	Span
+}
+
+// EraseStmt is an Erase statement.
+type EraseStmt struct {
+	Span
+	Vars []Expr
+}
+
+// RandomizeStmt is a Randomize statement.
+type RandomizeStmt struct {
+	Span
+	Seed Expr // nil if not given
+}
+
+// ExecuteStmt is an Execute or ExecuteGlobal statement.
+type ExecuteStmt struct {
+	Span
+	Global bool
+	Code   Expr
+}
+
+// StopStmt is a Stop statement.
+type StopStmt struct {
+	Span
+}
+
+// BadStmt stands in for a statement that could not be parsed.
+type BadStmt struct {
+	Span
+}
+
+func (*HTMLStmt) stmtNode()           {}
+func (*IncludeStmt) stmtNode()        {}
+func (*OutputStmt) stmtNode()         {}
+func (*DirectiveStmt) stmtNode()      {}
+func (*DimStmt) stmtNode()            {}
+func (*ReDimStmt) stmtNode()          {}
+func (*ConstStmt) stmtNode()          {}
+func (*ProcDecl) stmtNode()           {}
+func (*ClassDecl) stmtNode()          {}
+func (*AssignStmt) stmtNode()         {}
+func (*CallStmt) stmtNode()           {}
+func (*IfStmt) stmtNode()             {}
+func (*SelectStmt) stmtNode()         {}
+func (*ForStmt) stmtNode()            {}
+func (*ForEachStmt) stmtNode()        {}
+func (*DoStmt) stmtNode()             {}
+func (*WhileStmt) stmtNode()          {}
+func (*WithStmt) stmtNode()           {}
+func (*OnErrorStmt) stmtNode()        {}
+func (*ExitStmt) stmtNode()           {}
+func (*OptionExplicitStmt) stmtNode() {}
+func (*EraseStmt) stmtNode()          {}
+func (*RandomizeStmt) stmtNode()      {}
+func (*ExecuteStmt) stmtNode()        {}
+func (*StopStmt) stmtNode()           {}
+func (*BadStmt) stmtNode()            {}
@@ -0,0 +1,281 @@
+package vbparser
+
+import (
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// binaryLevels lists the binary operators from lowest to highest precedence.
+// Operators on the same level are left associative.
+var binaryLevels = [][]string{
+	{"Imp"},
+	{"Eqv"},
+	{"Xor"},
+	{"Or"},
+	{"And"},
+	{"=", "<>", "<", ">", "<=", ">=", "Is"}, // Not binds more loosely than these
+	{"&"},
+	{"+", "-"},
+	{"Mod"},
+	{"\\"},
+	{"*", "/"},
+}
+
+// compareLevel is the level of the comparison operators in binaryLevels.
+const compareLevel = 5
+
+// parseExpr parses an expression.
+func (p *parser) parseExpr() Expr {
+	return p.parseBinary(0)
+}
+
+// parseExprList parses expressions separated by commas.
+func (p *parser) parseExprList() []Expr {
+	list := []Expr{p.parseExpr()}
+	for p.tok.Type == vblexer.LIST_SEP {
+		p.next()
+		list = append(list, p.parseExpr())
+	}
+	return list
+}
+
+// parseArgList parses arguments separated by commas, any of which may be omitted.
+func (p *parser) parseArgList() []Expr {
+	var list []Expr
+	for {
+		if p.tok.Type == vblexer.LIST_SEP || p.tok.Type == vblexer.PAREN_CLOSE {
+			list = append(list, &OmittedExpr{Span: Span{StartPos: p.tok.Start, EndPos: p.tok.Start}})
+		} else {
+			list = append(list, p.parseExpr())
+		}
+		if p.tok.Type != vblexer.LIST_SEP {
+			return list
+		}
+		p.next()
+	}
+}
+
+// parseArgs parses a parenthesized, possibly empty, list of arguments.
+func (p *parser) parseArgs() []Expr {
+	p.expect(vblexer.PAREN_OPEN, "(")
+	var list []Expr
+	if p.tok.Type != vblexer.PAREN_CLOSE {
+		list = p.parseArgList()
+	}
+	p.expect(vblexer.PAREN_CLOSE, ")")
+	return list
+}
+
+// binaryOp returns the operator of the current token if it is on the given level.
+func (p *parser) binaryOp(level int) string {
+	var op string
+	switch {
+	case p.tok.Type == vblexer.OP:
+		op = p.tok.Value.(string)
+	case isStmt(p.tok, "Is"):
+		op = "Is"
+	default:
+		return ""
+	}
+	for _, o := range binaryLevels[level] {
+		if o == op {
+			return op
+		}
+	}
+	return ""
+}
+
+// parseBinary parses binary operations on the given level and above.
+func (p *parser) parseBinary(level int) Expr {
+	if level == len(binaryLevels) {
+		return p.parseNegate()
+	}
+	if level == compareLevel && isOp(p.tok, "Not") {
+		return p.parseNot()
+	}
+	x := p.parseBinary(level + 1)
+	for op := p.binaryOp(level); op != ""; op = p.binaryOp(level) {
+		p.next()
+		var y Expr
+		if level < compareLevel && isOp(p.tok, "Not") {
+			y = p.parseNot()
+		} else {
+			y = p.parseBinary(level + 1)
+		}
+		x = &BinaryExpr{Span: Span{StartPos: x.Pos(), EndPos: p.prevEnd}, Op: op, X: x, Y: y}
+	}
+	return x
+}
+
+// parseNot parses Not, which binds more loosely than comparisons.
+func (p *parser) parseNot() Expr {
+	start := p.tok.Start
+	p.next()
+	x := p.parseBinary(compareLevel)
+	return &UnaryExpr{Span: p.span(start), Op: "Not", X: x}
+}
+
+// parseNegate parses unary minus and plus, which bind more loosely than ^.
+func (p *parser) parseNegate() Expr {
+	if isOp(p.tok, "-") || isOp(p.tok, "+") {
+		start, op := p.tok.Start, p.tok.Value.(string)
+		p.next()
+		x := p.parseNegate()
+		return &UnaryExpr{Span: p.span(start), Op: op, X: x}
+	}
+	x := p.parsePostfix(true)
+	for isOp(p.tok, "^") {
+		p.next()
+		y := p.parseExponent()
+		x = &BinaryExpr{Span: Span{StartPos: x.Pos(), EndPos: p.prevEnd}, Op: "^", X: x, Y: y}
+	}
+	return x
+}
+
+// parseExponent parses the right side of ^, which may be negated, as in 2 ^ -1.
+func (p *parser) parseExponent() Expr {
+	if isOp(p.tok, "-") || isOp(p.tok, "+") {
+		start, op := p.tok.Start, p.tok.Value.(string)
+		p.next()
+		x := p.parseExponent()
+		return &UnaryExpr{Span: p.span(start), Op: op, X: x}
+	}
+	return p.parsePostfix(true)
+}
+
+// parseTarget parses the target of an assignment or the procedure called by
+// a statement. Parentheses after a space are not part of the target, since
+// they begin the first argument, as in foo (1), 2.
+func (p *parser) parseTarget() Expr {
+	switch p.tok.Type {
+	case vblexer.IDENTIFIER, vblexer.FUNCTION, vblexer.INTRINSIC_OBJECT, vblexer.INTRINSIC_MEMBER, vblexer.FIELD_SEP:
+	default:
+		p.errorf(p.tok.Start, "expected statement, found %s", describe(p.tok))
+	}
+	return p.parsePostfix(false)
+}
+
+// parsePostfix parses a primary expression followed by any member accesses
+// and argument lists. If spaced is false, parentheses preceded by whitespace
+// are not treated as an argument list.
+func (p *parser) parsePostfix(spaced bool) Expr {
+	x := p.parsePrimary()
+	for {
+		switch {
+		case p.tok.Type == vblexer.PAREN_OPEN && (spaced || p.tok.Leading == ""):
+			args := p.parseArgs()
+			x = &CallOrIndexExpr{Span: Span{StartPos: x.Pos(), EndPos: p.prevEnd}, Fun: x, Args: args}
+		case p.tok.Type == vblexer.FIELD_SEP:
+			p.next()
+			x = p.parseMember(x)
+		default:
+			return x
+		}
+	}
+}
+
+// parseMember parses the name after a dot. In dotted identifier mode the lexer
+// keeps names like rs.Fields.Item together, so they are split into member
+// accesses on x.
+func (p *parser) parseMember(x Expr) Expr {
+	t := p.memberName()
+	return dotted(x, t.Raw, t.Start)
+}
+
+// memberName consumes the name of a member. Members may have the same
+// names as keywords and builtins, like rs.Close or Err.Number.
+func (p *parser) memberName() vblexer.Token {
+	t := p.tok
+	switch t.Type {
+	case vblexer.IDENTIFIER, vblexer.FUNCTION, vblexer.STATEMENT, vblexer.KEYWORD, vblexer.KEYWORD_BOOL, vblexer.INTRINSIC_OBJECT, vblexer.INTRINSIC_MEMBER:
+	default:
+		if !isConstant(t.Type) {
+			p.errorf(t.Start, "expected member name, found %s", describe(t))
+		}
+	}
+	p.next()
+	return t
+}
+
+// dotted builds member accesses on x for each part of a dotted name starting
+// at pos. If x is nil, the first part is a plain identifier.
+func dotted(x Expr, name string, pos vbscanner.Position) Expr {
+	start := pos
+	if x != nil {
+		start = x.Pos()
+	}
+	for i, part := range strings.Split(name, ".") {
+		if i > 0 {
+			pos = pos.Advance(".")
+		}
+		end := pos.Advance(part)
+		id := &Ident{Span: Span{StartPos: pos, EndPos: end}, Name: part}
+		if x == nil && i == 0 {
+			x = id
+		} else {
+			x = &MemberExpr{Span: Span{StartPos: start, EndPos: end}, X: x, Name: id}
+		}
+		pos = end
+	}
+	return x
+}
+
+// isConstant returns true for the builtin constant token types, like vbCrLf,
+// and the ADO and FileSystemObject constants, like adOpenStatic.
+func isConstant(t vblexer.TokenType) bool {
+	return t >= vblexer.COLOR_CONSTANT && t <= vblexer.VARTYPE_CONSTANT || t == vblexer.ADO_CONSTANT || t == vblexer.FSO_CONSTANT
+}
+
+// parsePrimary parses a name, literal, parenthesized expression, New, or a
+// member of the With object.
+func (p *parser) parsePrimary() Expr {
+	t := p.tok
+	switch t.Type {
+	case vblexer.IDENTIFIER, vblexer.FUNCTION, vblexer.INTRINSIC_OBJECT, vblexer.INTRINSIC_MEMBER:
+		p.next()
+		return dotted(nil, t.Raw, t.Start)
+	case vblexer.STRING, vblexer.INT, vblexer.FLOAT, vblexer.DATE, vblexer.KEYWORD, vblexer.KEYWORD_BOOL:
+		p.next()
+		return &BasicLit{Span: Span{StartPos: t.Start, EndPos: t.End}, Kind: t.Type, Value: t.Value, Raw: t.Raw}
+	case vblexer.ERROR:
+		p.next()
+		return &BadExpr{Span: Span{StartPos: t.Start, EndPos: t.End}}
+	case vblexer.PAREN_OPEN:
+		p.next()
+		x := p.parseExpr()
+		p.expect(vblexer.PAREN_CLOSE, ")")
+		return &ParenExpr{Span: p.span(t.Start), X: x}
+	case vblexer.FIELD_SEP:
+		// a member of the With object
+		p.next()
+		n := p.memberName()
+		first, rest, _ := strings.Cut(n.Raw, ".")
+		end := n.Start.Advance(first)
+		var x Expr = &MemberExpr{
+			Span: Span{StartPos: t.Start, EndPos: end},
+			Name: &Ident{Span: Span{StartPos: n.Start, EndPos: end}, Name: first},
+		}
+		if rest != "" {
+			x = dotted(x, rest, end.Advance("."))
+		}
+		return x
+	case vblexer.STATEMENT:
+		if isStmt(t, "New") {
+			p.next()
+			c := p.ident()
+			return &NewExpr{Span: p.span(t.Start), Class: c}
+		}
+	case vblexer.OP:
+		if isOp(t, "Not") {
+			return p.parseNot()
+		}
+	}
+	if isConstant(t.Type) {
+		p.next()
+		return &Ident{Span: Span{StartPos: t.Start, EndPos: t.End}, Name: t.Raw}
+	}
+	p.errorf(t.Start, "expected expression, found %s", describe(t))
+	return nil
+}
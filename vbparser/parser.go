@@ -0,0 +1,869 @@
+// Package vbparser implements a parser for VBScript and ASP pages.
+package vbparser
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// Error is a syntax error found by the parser, or a problem found by the lexer.
+type Error struct {
+	Filename string
+	Pos      vbscanner.Position
+	Msg      string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%s: %s", e.Filename, e.Pos, e.Msg)
+}
+
+// ErrorList is a list of errors in a file, in order of position.
+type ErrorList []*Error
+
+// Error implements the error interface, describing the first error.
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// ParseFile parses an ASP page or VBScript file read from src.
+func ParseFile(filename string, src io.Reader, initialMode vbscanner.Mode) (*File, error) {
+	var lex vblexer.Lex
+	lex.Init(src, filename, initialMode)
+	return Parse(&lex)
+}
+
+// Parse parses the tokens read from lex. The parser recovers from a syntax
+// error by skipping to the next statement, so that it finds every problem
+// in the file. If there are any, err is an ErrorList holding them along
+// with those found by the lexer, and f holds what could be parsed, with a
+// BadStmt or BadExpr in place of what could not.
+func Parse(lex *vblexer.Lex) (f *File, err error) {
+	p := parser{lex: lex}
+	f = &File{Name: lex.Filename}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*Error)
+			if !ok {
+				panic(r)
+			}
+			p.errs = append(p.errs, e)
+		}
+		f.Comments = p.comments
+		if list := p.errors(); len(list) > 0 {
+			err = list
+		}
+	}()
+	p.next()
+	p.parseFile(f)
+	return f, nil
+}
+
+// parser holds the state of the parse.
+type parser struct {
+	lex      *vblexer.Lex
+	tok      vblexer.Token      // current token
+	prev     vblexer.Token      // previous token
+	prevEnd  vbscanner.Position // end of the previous token
+	depth    int                // number of statement lists being parsed
+	comments []*vblexer.Token
+	errs     ErrorList // syntax errors found so far
+}
+
+// ----------------------------------------------------------------------------
+// Token handling
+
+// next advances to the next significant token. Comments are collected
+// and line continuations are dropped along with the end of line after them.
+func (p *parser) next() {
+	p.prev = p.tok
+	p.prevEnd = p.tok.End
+	for {
+		t := p.lex.Next()
+		switch t.Type {
+		case vblexer.COMMENT:
+			p.comments = append(p.comments, &t)
+			continue
+		case vblexer.CONTINUATION:
+			if n := p.lex.Peek(0); n.Type == vblexer.EOL && n.Value == "\n" {
+				p.lex.Next()
+				continue
+			}
+		}
+		p.tok = t
+		return
+	}
+}
+
+// peek returns the significant token after the current one.
+func (p *parser) peek() vblexer.Token {
+	for i := 0; ; i++ {
+		t := p.lex.Peek(i)
+		if t.Type == vblexer.CONTINUATION {
+			if n := p.lex.Peek(i + 1); n.Type == vblexer.EOL && n.Value == "\n" {
+				i++
+				continue
+			}
+			return t
+		} else if t.Type != vblexer.COMMENT {
+			return t
+		}
+	}
+}
+
+// error records an error at pos and carries on.
+func (p *parser) error(pos vbscanner.Position, format string, args ...interface{}) {
+	p.errs = append(p.errs, &Error{Filename: p.lex.Filename, Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+// errorf stops the statement being parsed with an error at pos.
+func (p *parser) errorf(pos vbscanner.Position, format string, args ...interface{}) {
+	panic(&Error{Filename: p.lex.Filename, Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+// errors returns the problems found by the lexer and the syntax errors in
+// order of position. Only the first error at a position is kept, since the
+// others follow from it.
+func (p *parser) errors() ErrorList {
+	var list ErrorList
+	for _, e := range p.lex.Errors() {
+		list = append(list, &Error{Filename: e.Filename, Pos: e.Pos, Msg: e.Msg})
+	}
+	list = append(list, p.errs...)
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i].Pos, list[j].Pos
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return slices.CompactFunc(list, func(a, b *Error) bool {
+		return a.Pos.Line == b.Pos.Line && a.Pos.Column == b.Pos.Column
+	})
+}
+
+// describe returns a description of t for error messages.
+func describe(t vblexer.Token) string {
+	switch t.Type {
+	case vblexer.EOF:
+		return "end of file"
+	case vblexer.EOL:
+		return "end of statement"
+	case vblexer.HTML, vblexer.SCRIPT_TAG:
+		return "end of script block"
+	}
+	return fmt.Sprintf("[%s]", t.Raw)
+}
+
+// isStmt returns true if t is the given statement keyword, like "If".
+func isStmt(t vblexer.Token, name string) bool {
+	return t.Type == vblexer.STATEMENT && t.Value == name
+}
+
+// isWord returns true if t is an identifier with the given name.
+// It is used for words that the lexer does not treat as statements, like "Preserve".
+func isWord(t vblexer.Token, name string) bool {
+	return t.Type == vblexer.IDENTIFIER && strings.EqualFold(t.Raw, name)
+}
+
+// isOp returns true if t is the given operator.
+func isOp(t vblexer.Token, op string) bool {
+	return t.Type == vblexer.OP && t.Value == op
+}
+
+// expectStmt consumes the given statement keyword.
+func (p *parser) expectStmt(name string) {
+	if !isStmt(p.tok, name) {
+		p.errorf(p.tok.Start, "expected %s, found %s", name, describe(p.tok))
+	}
+	p.next()
+}
+
+// expect consumes a token of the given type.
+func (p *parser) expect(t vblexer.TokenType, what string) vblexer.Token {
+	tok := p.tok
+	if tok.Type != t {
+		p.errorf(tok.Start, "expected %s, found %s", what, describe(tok))
+	}
+	p.next()
+	return tok
+}
+
+// atEOS returns true if the current token ends a statement.
+func (p *parser) atEOS() bool {
+	switch p.tok.Type {
+	case vblexer.EOL, vblexer.EOF, vblexer.HTML, vblexer.FILE_INCLUDE, vblexer.VIRTUAL_INCLUDE, vblexer.SCRIPT_TAG:
+		return true
+	}
+	return false
+}
+
+// expectEOS consumes the end of a statement. HTML and includes end a
+// statement but are statements themselves, so they are left in place.
+func (p *parser) expectEOS() {
+	if !p.atEOS() {
+		p.errorf(p.tok.Start, "expected end of statement, found %s", describe(p.tok))
+	}
+	if p.tok.Type == vblexer.EOL {
+		p.next()
+	}
+}
+
+// atEnd returns true if the current tokens are End followed by name, like "End If".
+func (p *parser) atEnd(name string) bool {
+	return isStmt(p.tok, "End") && isStmt(p.peek(), name)
+}
+
+// expectEnd consumes End followed by name. If they are missing, the error
+// is recorded and the block is taken to end there, leaving the tokens for
+// the enclosing block.
+func (p *parser) expectEnd(name string) {
+	if !p.atEnd(name) {
+		p.error(p.tok.Start, "expected End %s, found %s", name, describe(p.tok))
+		return
+	}
+	p.next()
+	p.next()
+}
+
+// span returns a span from start to the end of the previous token.
+func (p *parser) span(start vbscanner.Position) Span {
+	return Span{StartPos: start, EndPos: p.prevEnd}
+}
+
+// ident consumes a name. Builtin function names are accepted too,
+// since classes may declare members with those names, as are ADO and
+// FileSystemObject constants, which pages declare with Const, and the
+// intrinsic object names, which are only reserved in ASP.
+func (p *parser) ident() *Ident {
+	t := p.tok
+	switch t.Type {
+	case vblexer.IDENTIFIER, vblexer.FUNCTION, vblexer.ADO_CONSTANT, vblexer.FSO_CONSTANT, vblexer.INTRINSIC_OBJECT:
+	default:
+		p.errorf(t.Start, "expected identifier, found %s", describe(t))
+	}
+	p.next()
+	return &Ident{Span: Span{StartPos: t.Start, EndPos: t.End}, Name: t.Raw}
+}
+
+// ----------------------------------------------------------------------------
+// Statements
+
+// parseFile parses the whole input into f, which keeps what was parsed if
+// an error stops the parse.
+func (p *parser) parseFile(f *File) {
+	f.StartPos = p.tok.Start
+	f.Body = p.parseStmtList(func() bool { return false })
+	f.EndPos = p.tok.End
+	if p.tok.Type != vblexer.EOF {
+		p.errorf(p.tok.Start, "unexpected %s", describe(p.tok))
+	}
+}
+
+// parseStmtList parses statements until the end of the file or until
+// done returns true at the start of a statement. Within a block it also
+// stops at End, Next, Loop and Wend, so that a block missing its own end
+// leaves the end of the enclosing block in place.
+func (p *parser) parseStmtList(done func() bool) []Stmt {
+	p.depth++
+	defer func() { p.depth-- }()
+	var list []Stmt
+	for {
+		for p.tok.Type == vblexer.EOL {
+			p.next()
+		}
+		if p.tok.Type == vblexer.EOF || done() || p.depth > 1 && p.tok.Type == vblexer.STATEMENT && slices.Contains(endKeywords, p.tok.Value.(string)) {
+			return list
+		}
+		if s := p.recoverStmt(); s != nil {
+			list = append(list, s)
+		}
+	}
+}
+
+// recoverStmt parses a statement and the end of statement after it. If a
+// syntax error stops the statement, the error is recorded and the rest of
+// the statement is skipped. A BadStmt takes the place of a statement that
+// could not be parsed.
+func (p *parser) recoverStmt() (s Stmt) {
+	start := p.tok
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*Error)
+			if !ok {
+				panic(r)
+			}
+			p.errs = append(p.errs, e)
+			p.sync(start)
+			if s == nil {
+				s = &BadStmt{Span: p.span(start.Start)}
+			}
+			if p.tok.Type == vblexer.EOL {
+				p.next()
+			}
+		}
+	}()
+	s = p.parseStmt()
+	switch s.(type) {
+	case nil, *HTMLStmt, *IncludeStmt:
+	default:
+		if !p.reported() {
+			p.expectEOS()
+		}
+	}
+	return s
+}
+
+// reported returns true if an error was recorded at the current token, as
+// when a block is missing its end.
+func (p *parser) reported() bool {
+	return len(p.errs) > 0 && p.errs[len(p.errs)-1].Pos == p.tok.Start
+}
+
+// stmtKeywords holds the statements that begin a statement, afterKeywords
+// those that may come before them in another statement, like End in End If,
+// and endKeywords those that end a block.
+var (
+	stmtKeywords  = []string{"Call", "Class", "Const", "Dim", "Do", "Erase", "Execute", "ExecuteGlobal", "Exit", "For", "Function", "If", "On", "Option", "Private", "Property", "Public", "Randomize", "ReDim", "Select", "Set", "Stop", "Sub", "While", "With"}
+	afterKeywords = []string{"Do", "End", "Exit", "Loop", "Private", "Property", "Public"}
+	endKeywords   = []string{"End", "Loop", "Next", "WEnd"}
+)
+
+// sync skips the rest of a statement that could not be parsed, which began
+// with start. It stops at the end of the line or of the script block, or at
+// a keyword that begins another statement.
+func (p *parser) sync(start vblexer.Token) {
+	if p.tok.Start == start.Start && !p.atEOS() {
+		p.next()
+	}
+	for !p.atEOS() {
+		if p.tok.Type == vblexer.STATEMENT && slices.Contains(stmtKeywords, p.tok.Value.(string)) &&
+			!(p.prev.Type == vblexer.STATEMENT && slices.Contains(afterKeywords, p.prev.Value.(string))) {
+			return
+		}
+		p.next()
+	}
+}
+
+// parseStmt parses a single statement. It returns nil for empty HTML and
+// for the tags around server script blocks.
+func (p *parser) parseStmt() Stmt {
+	t := p.tok
+	switch t.Type {
+	case vblexer.HTML:
+		p.next()
+		if t.Raw == "" {
+			return nil
+		}
+		return &HTMLStmt{Span: Span{StartPos: t.Start, EndPos: t.End}, Text: t.Raw}
+	case vblexer.SCRIPT_TAG:
+		p.next()
+		return nil
+	case vblexer.FILE_INCLUDE, vblexer.VIRTUAL_INCLUDE:
+		p.next()
+		return &IncludeStmt{Span: Span{StartPos: t.Start, EndPos: t.End}, Virtual: t.Type == vblexer.VIRTUAL_INCLUDE, Path: t.Raw}
+	case vblexer.OUTPUT:
+		p.next()
+		v := p.parseExpr()
+		return &OutputStmt{Span: p.span(t.Start), Value: v}
+	case vblexer.DIRECTIVE:
+		p.next()
+		return &DirectiveStmt{Span: Span{StartPos: t.Start, EndPos: t.End}, Attrs: t.Value.(map[string]string)}
+	case vblexer.STATEMENT:
+		switch t.Value {
+		case "Dim":
+			p.next()
+			vars := p.parseVarList()
+			return &DimStmt{Span: p.span(t.Start), Vars: vars}
+		case "ReDim":
+			return p.parseReDim()
+		case "Const":
+			return p.parseConst(t.Start, AccessNone)
+		case "Public", "Private":
+			return p.parseAccess()
+		case "Sub", "Function", "Property":
+			return p.parseProc(t.Start, AccessNone, false)
+		case "Class":
+			return p.parseClass(t.Start)
+		case "If":
+			return p.parseIf()
+		case "Select":
+			return p.parseSelect()
+		case "For":
+			return p.parseFor()
+		case "Do":
+			return p.parseDo()
+		case "While":
+			return p.parseWhile()
+		case "With":
+			return p.parseWith()
+		case "On":
+			return p.parseOnError()
+		case "Exit":
+			return p.parseExit()
+		case "Call":
+			return p.parseCall()
+		case "Set", "Let":
+			return p.parseAssign()
+		case "Option":
+			p.next()
+			p.expectStmt("Explicit")
+			return &OptionExplicitStmt{Span: p.span(t.Start)}
+		case "Erase":
+			p.next()
+			vars := p.parseExprList()
+			return &EraseStmt{Span: p.span(t.Start), Vars: vars}
+		case "Randomize":
+			p.next()
+			s := &RandomizeStmt{}
+			if !p.atEOS() && !isStmt(p.tok, "Else") {
+				s.Seed = p.parseExpr()
+			}
+			s.Span = p.span(t.Start)
+			return s
+		case "Execute", "ExecuteGlobal":
+			p.next()
+			code := p.parseExpr()
+			return &ExecuteStmt{Span: p.span(t.Start), Global: t.Value == "ExecuteGlobal", Code: code}
+		case "Stop":
+			p.next()
+			return &StopStmt{Span: p.span(t.Start)}
+		default:
+			p.errorf(t.Start, "unexpected %s", describe(t))
+		}
+	}
+	return p.parseSimple()
+}
+
+// parseSimple parses an assignment or a call without the Call keyword.
+func (p *parser) parseSimple() Stmt {
+	start := p.tok.Start
+	target := p.parseTarget()
+	if isOp(p.tok, "=") {
+		p.next()
+		v := p.parseExpr()
+		return &AssignStmt{Span: p.span(start), Target: target, Value: v}
+	}
+	s := &CallStmt{Callee: target}
+	if c, ok := target.(*CallOrIndexExpr); ok {
+		// the final parentheses hold the arguments, as in foo(1), but
+		// foo(1), 2 passes (1) as the first of several arguments
+		s.Callee, s.Args = c.Fun, c.Args
+		if p.tok.Type == vblexer.LIST_SEP && len(c.Args) == 1 {
+			s.Args = []Expr{&ParenExpr{Span: Span{StartPos: c.Fun.End(), EndPos: c.End()}, X: c.Args[0]}}
+			p.next()
+			s.Args = append(s.Args, p.parseArgList()...)
+		}
+	} else if !p.atEOS() && !isStmt(p.tok, "Else") {
+		s.Args = p.parseArgList()
+	}
+	s.Span = p.span(start)
+	return s
+}
+
+// parseCall parses a Call statement.
+func (p *parser) parseCall() Stmt {
+	start := p.tok.Start
+	p.next()
+	s := &CallStmt{Call: true, Callee: p.parsePostfix(true)}
+	if c, ok := s.Callee.(*CallOrIndexExpr); ok {
+		s.Callee, s.Args = c.Fun, c.Args
+	}
+	s.Span = p.span(start)
+	return s
+}
+
+// parseAssign parses an assignment starting with Set or Let.
+func (p *parser) parseAssign() Stmt {
+	start := p.tok.Start
+	set := isStmt(p.tok, "Set")
+	p.next()
+	target := p.parseTarget()
+	if !isOp(p.tok, "=") {
+		p.errorf(p.tok.Start, "expected =, found %s", describe(p.tok))
+	}
+	p.next()
+	v := p.parseExpr()
+	return &AssignStmt{Span: p.span(start), Set: set, Target: target, Value: v}
+}
+
+// parseVarList parses variable declarations separated by commas.
+func (p *parser) parseVarList() []*VarDecl {
+	var list []*VarDecl
+	for {
+		v := &VarDecl{Name: p.ident()}
+		if p.tok.Type == vblexer.PAREN_OPEN {
+			v.Array = true
+			v.Bounds = p.parseArgs()
+		}
+		v.Span = p.span(v.Name.Pos())
+		list = append(list, v)
+		if p.tok.Type != vblexer.LIST_SEP {
+			return list
+		}
+		p.next()
+	}
+}
+
+// parseReDim parses a ReDim statement.
+func (p *parser) parseReDim() Stmt {
+	start := p.tok.Start
+	p.next()
+	s := &ReDimStmt{}
+	if isWord(p.tok, "Preserve") {
+		s.Preserve = true
+		p.next()
+	}
+	s.Vars = p.parseVarList()
+	s.Span = p.span(start)
+	return s
+}
+
+// parseConst parses a Const statement; start is the position of any access modifier.
+func (p *parser) parseConst(start vbscanner.Position, access Access) Stmt {
+	p.expectStmt("Const")
+	s := &ConstStmt{Access: access}
+	for {
+		c := &ConstDecl{Name: p.ident()}
+		if !isOp(p.tok, "=") {
+			p.errorf(p.tok.Start, "expected =, found %s", describe(p.tok))
+		}
+		p.next()
+		c.Value = p.parseExpr()
+		c.Span = p.span(c.Name.Pos())
+		s.Consts = append(s.Consts, c)
+		if p.tok.Type != vblexer.LIST_SEP {
+			break
+		}
+		p.next()
+	}
+	s.Span = p.span(start)
+	return s
+}
+
+// parseAccess parses a declaration that starts with Public or Private.
+func (p *parser) parseAccess() Stmt {
+	start := p.tok.Start
+	access := AccessPublic
+	if isStmt(p.tok, "Private") {
+		access = AccessPrivate
+	}
+	p.next()
+	def := false
+	if access == AccessPublic && isWord(p.tok, "Default") {
+		def = true
+		p.next()
+	}
+	switch {
+	case isStmt(p.tok, "Sub"), isStmt(p.tok, "Function"), isStmt(p.tok, "Property"):
+		return p.parseProc(start, access, def)
+	case def:
+		p.errorf(p.tok.Start, "expected Sub, Function or Property, found %s", describe(p.tok))
+	case isStmt(p.tok, "Const"):
+		return p.parseConst(start, access)
+	case isStmt(p.tok, "Class"):
+		return p.parseClass(start)
+	}
+	vars := p.parseVarList()
+	return &DimStmt{Span: p.span(start), Access: access, Vars: vars}
+}
+
+// parseProc parses a Sub, Function or Property declaration.
+func (p *parser) parseProc(start vbscanner.Position, access Access, def bool) Stmt {
+	s := &ProcDecl{Access: access, Default: def}
+	end := p.tok.Value.(string)
+	switch end {
+	case "Sub":
+		s.Kind = SubProc
+	case "Function":
+		s.Kind = FunctionProc
+	case "Property":
+		p.next()
+		switch {
+		case isStmt(p.tok, "Get"):
+			s.Kind = PropertyGetProc
+		case isStmt(p.tok, "Let"):
+			s.Kind = PropertyLetProc
+		case isStmt(p.tok, "Set"):
+			s.Kind = PropertySetProc
+		default:
+			p.errorf(p.tok.Start, "expected Get, Let or Set, found %s", describe(p.tok))
+		}
+	}
+	p.next()
+	s.Name = p.ident()
+	if p.tok.Type == vblexer.PAREN_OPEN {
+		s.Params = p.parseParams()
+	}
+	p.expectEOS()
+	s.Body = p.parseStmtList(func() bool { return p.atEnd(end) })
+	p.expectEnd(end)
+	s.Span = p.span(start)
+	return s
+}
+
+// parseParams parses a parenthesized parameter list.
+func (p *parser) parseParams() []*Param {
+	p.expect(vblexer.PAREN_OPEN, "(")
+	var list []*Param
+	for p.tok.Type != vblexer.PAREN_CLOSE {
+		if len(list) > 0 {
+			p.expect(vblexer.LIST_SEP, ",")
+		}
+		prm := &Param{}
+		start := p.tok.Start
+		if isStmt(p.tok, "ByVal") {
+			prm.ByVal = true
+			p.next()
+		} else if isStmt(p.tok, "ByRef") {
+			prm.ByRef = true
+			p.next()
+		}
+		prm.Name = p.ident()
+		if p.tok.Type == vblexer.PAREN_OPEN {
+			p.next()
+			p.expect(vblexer.PAREN_CLOSE, ")")
+			prm.Array = true
+		}
+		prm.Span = p.span(start)
+		list = append(list, prm)
+	}
+	p.next()
+	return list
+}
+
+// parseClass parses a Class declaration; start is the position of any access modifier.
+func (p *parser) parseClass(start vbscanner.Position) Stmt {
+	p.next()
+	s := &ClassDecl{Name: p.ident()}
+	p.expectEOS()
+	s.Members = p.parseStmtList(func() bool { return p.atEnd("Class") })
+	p.expectEnd("Class")
+	s.Span = p.span(start)
+	return s
+}
+
+// parseIf parses a block or single line If statement.
+func (p *parser) parseIf() Stmt {
+	start := p.tok.Start
+	p.next()
+	s := &IfStmt{Cond: p.parseExpr()}
+	p.expectStmt("Then")
+	if !p.atEOS() {
+		s.SingleLine = true
+		s.Then = p.parseLine()
+		if isStmt(p.tok, "Else") {
+			p.next()
+			s.Else = p.parseLine()
+		}
+		if p.atEnd("If") {
+			p.expectEnd("If")
+		}
+		s.Span = p.span(start)
+		return s
+	}
+	p.expectEOS()
+	endOfBlock := func() bool {
+		return isStmt(p.tok, "ElseIf") || isStmt(p.tok, "Else") || p.atEnd("If")
+	}
+	s.Then = p.parseStmtList(endOfBlock)
+	for isStmt(p.tok, "ElseIf") {
+		c := &ElseIfClause{}
+		cstart := p.tok.Start
+		p.next()
+		c.Cond = p.parseExpr()
+		p.expectStmt("Then")
+		p.expectEOS()
+		c.Body = p.parseStmtList(endOfBlock)
+		c.Span = p.span(cstart)
+		s.ElseIfs = append(s.ElseIfs, c)
+	}
+	if isStmt(p.tok, "Else") {
+		p.next()
+		s.Else = p.parseStmtList(func() bool { return p.atEnd("If") })
+	}
+	p.expectEnd("If")
+	s.Span = p.span(start)
+	return s
+}
+
+// parseLine parses the statements of a single line If, which are separated by
+// colons and end at the end of the line or at Else.
+func (p *parser) parseLine() []Stmt {
+	var list []Stmt
+	for {
+		list = append(list, p.parseStmt())
+		if p.tok.Type != vblexer.EOL || p.tok.Value != ":" {
+			return list
+		}
+		p.next()
+		if isStmt(p.tok, "Else") || p.atEnd("If") || p.atEOS() {
+			return list
+		}
+	}
+}
+
+// parseSelect parses a Select Case statement.
+func (p *parser) parseSelect() Stmt {
+	start := p.tok.Start
+	p.next()
+	p.expectStmt("Case")
+	s := &SelectStmt{Value: p.parseExpr()}
+	p.expectEOS()
+	for {
+		for p.tok.Type == vblexer.EOL {
+			p.next()
+		}
+		if !isStmt(p.tok, "Case") {
+			break
+		}
+		c := &CaseClause{}
+		cstart := p.tok.Start
+		p.next()
+		if isStmt(p.tok, "Else") {
+			c.Else = true
+			p.next()
+		} else {
+			c.Values = p.parseExprList()
+		}
+		p.expectEOS()
+		c.Body = p.parseStmtList(func() bool { return isStmt(p.tok, "Case") || p.atEnd("Select") })
+		c.Span = p.span(cstart)
+		s.Cases = append(s.Cases, c)
+	}
+	p.expectEnd("Select")
+	s.Span = p.span(start)
+	return s
+}
+
+// parseFor parses a For or For Each loop.
+func (p *parser) parseFor() Stmt {
+	start := p.tok.Start
+	p.next()
+	if isStmt(p.tok, "Each") {
+		p.next()
+		s := &ForEachStmt{Var: p.ident()}
+		p.expectStmt("In")
+		s.In = p.parseExpr()
+		p.expectEOS()
+		s.Body = p.parseStmtList(func() bool { return isStmt(p.tok, "Next") })
+		p.expectStmt("Next")
+		s.Span = p.span(start)
+		return s
+	}
+	s := &ForStmt{Var: p.ident()}
+	if !isOp(p.tok, "=") {
+		p.errorf(p.tok.Start, "expected =, found %s", describe(p.tok))
+	}
+	p.next()
+	s.From = p.parseExpr()
+	p.expectStmt("To")
+	s.To = p.parseExpr()
+	if isStmt(p.tok, "Step") {
+		p.next()
+		s.Step = p.parseExpr()
+	}
+	p.expectEOS()
+	s.Body = p.parseStmtList(func() bool { return isStmt(p.tok, "Next") })
+	p.expectStmt("Next")
+	s.Span = p.span(start)
+	return s
+}
+
+// parseLoopCond parses an optional While or Until condition of a Do loop.
+func (p *parser) parseLoopCond() (LoopKind, Expr) {
+	switch {
+	case isStmt(p.tok, "While"):
+		p.next()
+		return LoopWhile, p.parseExpr()
+	case isWord(p.tok, "Until"):
+		p.next()
+		return LoopUntil, p.parseExpr()
+	}
+	return LoopForever, nil
+}
+
+// parseDo parses a Do ... Loop statement.
+func (p *parser) parseDo() Stmt {
+	start := p.tok.Start
+	p.next()
+	s := &DoStmt{}
+	s.Kind, s.Cond = p.parseLoopCond()
+	p.expectEOS()
+	s.Body = p.parseStmtList(func() bool { return isStmt(p.tok, "Loop") })
+	p.expectStmt("Loop")
+	if s.Kind == LoopForever {
+		s.Kind, s.Cond = p.parseLoopCond()
+		s.CondAtEnd = s.Kind != LoopForever
+	}
+	s.Span = p.span(start)
+	return s
+}
+
+// parseWhile parses a While ... Wend loop.
+func (p *parser) parseWhile() Stmt {
+	start := p.tok.Start
+	p.next()
+	s := &WhileStmt{Cond: p.parseExpr()}
+	p.expectEOS()
+	s.Body = p.parseStmtList(func() bool { return isStmt(p.tok, "WEnd") })
+	p.expectStmt("WEnd")
+	s.Span = p.span(start)
+	return s
+}
+
+// parseWith parses a With block.
+func (p *parser) parseWith() Stmt {
+	start := p.tok.Start
+	p.next()
+	s := &WithStmt{Object: p.parseExpr()}
+	p.expectEOS()
+	s.Body = p.parseStmtList(func() bool { return p.atEnd("With") })
+	p.expectEnd("With")
+	s.Span = p.span(start)
+	return s
+}
+
+// parseOnError parses On Error Resume Next or On Error GoTo 0.
+func (p *parser) parseOnError() Stmt {
+	start := p.tok.Start
+	p.next()
+	p.expectStmt("Error")
+	s := &OnErrorStmt{}
+	if isStmt(p.tok, "Resume") {
+		p.next()
+		p.expectStmt("Next")
+		s.ResumeNext = true
+	} else {
+		p.expectStmt("GoTo")
+		if p.tok.Type != vblexer.INT || p.tok.Raw != "0" {
+			p.errorf(p.tok.Start, "expected 0, found %s", describe(p.tok))
+		}
+		p.next()
+	}
+	s.Span = p.span(start)
+	return s
+}
+
+// parseExit parses an Exit statement.
+func (p *parser) parseExit() Stmt {
+	start := p.tok.Start
+	p.next()
+	t := p.tok
+	switch {
+	case isStmt(t, "Do"), isStmt(t, "For"), isStmt(t, "Function"), isStmt(t, "Sub"), isStmt(t, "Property"):
+	default:
+		p.errorf(t.Start, "expected Do, For, Function, Sub or Property, found %s", describe(t))
+	}
+	p.next()
+	return &ExitStmt{Span: p.span(start), Kind: t.Value.(string)}
+}
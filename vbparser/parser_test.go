@@ -0,0 +1,224 @@
+package vbparser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// render returns x with every operation in parentheses, to show how it was
+// grouped.
+func render(x Expr) string {
+	switch x := x.(type) {
+	case *Ident:
+		return x.Name
+	case *BasicLit:
+		return x.Raw
+	case *ParenExpr:
+		return "[" + render(x.X) + "]"
+	case *UnaryExpr:
+		if x.Op == "Not" {
+			return "(Not " + render(x.X) + ")"
+		}
+		return "(" + x.Op + render(x.X) + ")"
+	case *BinaryExpr:
+		return "(" + render(x.X) + " " + x.Op + " " + render(x.Y) + ")"
+	case *MemberExpr:
+		if x.X == nil {
+			return "." + x.Name.Name
+		}
+		return render(x.X) + "." + x.Name.Name
+	case *CallOrIndexExpr:
+		return render(x.Fun) + "(" + renderList(x.Args) + ")"
+	case *NewExpr:
+		return "New " + x.Class.Name
+	case *OmittedExpr:
+		return "_"
+	}
+	return fmt.Sprintf("%T", x)
+}
+
+func renderList(list []Expr) string {
+	s := make([]string, len(list))
+	for i, x := range list {
+		s[i] = render(x)
+	}
+	return strings.Join(s, ", ")
+}
+
+func parse(t *testing.T, src string) *File {
+	t.Helper()
+	f, err := ParseFile("test.vbs", strings.NewReader(src), vbscanner.VBS_MODE)
+	if err != nil {
+		t.Fatalf("%q: %v", src, err)
+	}
+	return f
+}
+
+func TestPrecedence(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"1 + 2 * 3", "(1 + (2 * 3))"},
+		{"1 - 2 - 3", "((1 - 2) - 3)"},
+		{"2 ^ 3 ^ 2", "((2 ^ 3) ^ 2)"},
+		{"-2 ^ 2", "(-(2 ^ 2))"},
+		{"2 ^ -1", "(2 ^ (-1))"},
+		{"- -a", "(-(-a))"},
+		{"a * -b", "(a * (-b))"},
+		{"7 \\ 2 * 3", "(7 \\ (2 * 3))"},
+		{"7 Mod 2 \\ 3", "(7 Mod (2 \\ 3))"},
+		{"a + 1 Mod 2", "(a + (1 Mod 2))"},
+		{"a & b + c", "(a & (b + c))"},
+		{"a & b = c", "((a & b) = c)"},
+		{"Not a = b", "(Not (a = b))"},
+		{"Not a And b", "((Not a) And b)"},
+		{"a And Not b = c", "(a And (Not (b = c)))"},
+		{"a Or b And c", "(a Or (b And c))"},
+		{"a Xor b Or c", "(a Xor (b Or c))"},
+		{"a Imp b Eqv c", "(a Imp (b Eqv c))"},
+		{"a Is Nothing Or b", "((a Is Nothing) Or b)"},
+		{"(1 + 2) * 3", "([(1 + 2)] * 3)"},
+		{"a.b(1).c", "a.b(1).c"},
+		{"f(, 2)", "f(_, 2)"},
+	}
+	for _, tt := range tests {
+		f := parse(t, "x = "+tt.in+"\n")
+		a, ok := f.Body[0].(*AssignStmt)
+		if !ok {
+			t.Errorf("%q: parsed as %T", tt.in, f.Body[0])
+			continue
+		}
+		if got := render(a.Value); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCallStmt(t *testing.T) {
+	tests := []struct {
+		in     string
+		call   bool
+		callee string
+		args   string
+	}{
+		{"foo", false, "foo", ""},
+		{"foo 1, 2", false, "foo", "1, 2"},
+		{"foo(1, 2)", false, "foo", "1, 2"},
+		{"foo (1), 2", false, "foo", "[1], 2"},
+		{"foo(1), 2", false, "foo", "[1], 2"},
+		{"foo (1)", false, "foo", "[1]"},
+		{"foo (1) + 2, 3", false, "foo", "([1] + 2), 3"},
+		{"foo , 2", false, "foo", "_, 2"},
+		{"Call foo(1, 2)", true, "foo", "1, 2"},
+		{"Call foo", true, "foo", ""},
+		{"obj.Method a(1), b", false, "obj.Method", "a(1), b"},
+		{".Method 1", false, ".Method", "1"},
+	}
+	for _, tt := range tests {
+		f := parse(t, tt.in+"\n")
+		s, ok := f.Body[0].(*CallStmt)
+		if !ok {
+			t.Errorf("%q: parsed as %T", tt.in, f.Body[0])
+			continue
+		}
+		if s.Call != tt.call || render(s.Callee) != tt.callee || renderList(s.Args) != tt.args {
+			t.Errorf("%q: got Call=%v %s(%s), want Call=%v %s(%s)", tt.in, s.Call, render(s.Callee), renderList(s.Args), tt.call, tt.callee, tt.args)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		in     string
+		errors int
+	}{
+		{"x = \nIf a Then\nEnd If\n", 1},
+		{"x = (1\ny = 2\nz = )\n", 2},
+		{"Sub Foo\nx = 1\n", 1},
+		{"x = #99/99/99#\n", 1},
+	}
+	for _, tt := range tests {
+		f, err := ParseFile("test.vbs", strings.NewReader(tt.in), vbscanner.VBS_MODE)
+		list, ok := err.(ErrorList)
+		if !ok || len(list) != tt.errors {
+			t.Errorf("%q: got %v, want %d errors", tt.in, err, tt.errors)
+		}
+		if f == nil || len(f.Body) == 0 {
+			t.Errorf("%q: no partial file", tt.in)
+		}
+	}
+}
+
+// shape returns the kinds of the statements in list and of those nested
+// in them, like "If{Assign}else{Call}".
+func shape(list []Stmt) string {
+	s := make([]string, len(list))
+	for i, st := range list {
+		name := strings.TrimSuffix(strings.TrimPrefix(fmt.Sprintf("%T", st), "*vbparser."), "Stmt")
+		switch st := st.(type) {
+		case *IfStmt:
+			name += "{" + shape(st.Then) + "}"
+			for _, c := range st.ElseIfs {
+				name += "elseif{" + shape(c.Body) + "}"
+			}
+			if st.Else != nil {
+				name += "else{" + shape(st.Else) + "}"
+			}
+		case *SelectStmt:
+			for _, c := range st.Cases {
+				if c.Else {
+					name += "else"
+				}
+				name += "{" + shape(c.Body) + "}"
+			}
+		case *ForStmt:
+			name += "{" + shape(st.Body) + "}"
+		case *ForEachStmt:
+			name += "{" + shape(st.Body) + "}"
+		case *DoStmt:
+			name += "{" + shape(st.Body) + "}"
+		case *WhileStmt:
+			name += "{" + shape(st.Body) + "}"
+		case *WithStmt:
+			name += "{" + shape(st.Body) + "}"
+		case *ProcDecl:
+			name += "{" + shape(st.Body) + "}"
+		case *ClassDecl:
+			name += "{" + shape(st.Members) + "}"
+		}
+		s[i] = name
+	}
+	return strings.Join(s, " ")
+}
+
+func TestStatements(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Dim a, b(3)\nReDim Preserve b(4)\nConst c = 1", "Dim ReDim Const"},
+		{"x = 1 : y = 2", "Assign Assign"},
+		{"Set o = Nothing", "Assign"},
+		{"If a Then x = 1 Else foo", "If{Assign}else{Call}"},
+		{"If a Then x = 1 : y = 2", "If{Assign Assign}"},
+		{"If a Then\nx = 1\nElseIf b Then\nfoo\nElse\nbar\nEnd If", "If{Assign}elseif{Call}else{Call}"},
+		{"Select Case x\nCase 1, 2\nfoo\nCase Else\nbar\nEnd Select", "Select{Call}else{Call}"},
+		{"For i = 1 To 10 Step 2\nExit For\nNext", "For{Exit}"},
+		{"For Each v In list\nfoo v\nNext", "ForEach{Call}"},
+		{"Do While a\nLoop\nDo\nLoop Until b", "Do{} Do{}"},
+		{"While a\nWend", "While{}"},
+		{"With o\n.Name = 1\nEnd With", "With{Assign}"},
+		{"On Error Resume Next\nOn Error GoTo 0", "OnError OnError"},
+		{"Sub Foo(ByVal a, ByRef b, c())\nExit Sub\nEnd Sub", "ProcDecl{Exit}"},
+		{"Class C\nPrivate x\nPublic Default Function F\nEnd Function\nProperty Get P\nEnd Property\nEnd Class", "ClassDecl{Dim ProcDecl{} ProcDecl{}}"},
+		{"Option Explicit\nErase a\nRandomize\nExecuteGlobal s\nStop", "OptionExplicit Erase Randomize Execute Stop"},
+	}
+	for _, tt := range tests {
+		f := parse(t, tt.in+"\n")
+		if got := shape(f.Body); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package vbparser
+
+// Inspect traverses the tree rooted at node in depth-first order. It calls
+// f for each node; if f returns true, Inspect visits the children of the node.
+func Inspect(node Node, f func(Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+	switch n := node.(type) {
+	case *File:
+		inspectStmts(n.Body, f)
+	case *ParenExpr:
+		Inspect(n.X, f)
+	case *UnaryExpr:
+		Inspect(n.X, f)
+	case *BinaryExpr:
+		Inspect(n.X, f)
+		Inspect(n.Y, f)
+	case *MemberExpr:
+		if n.X != nil {
+			Inspect(n.X, f)
+		}
+		Inspect(n.Name, f)
+	case *CallOrIndexExpr:
+		Inspect(n.Fun, f)
+		inspectExprs(n.Args, f)
+	case *NewExpr:
+		Inspect(n.Class, f)
+	case *OutputStmt:
+		Inspect(n.Value, f)
+	case *VarDecl:
+		Inspect(n.Name, f)
+		inspectExprs(n.Bounds, f)
+	case *DimStmt:
+		for _, v := range n.Vars {
+			Inspect(v, f)
+		}
+	case *ReDimStmt:
+		for _, v := range n.Vars {
+			Inspect(v, f)
+		}
+	case *ConstDecl:
+		Inspect(n.Name, f)
+		Inspect(n.Value, f)
+	case *ConstStmt:
+		for _, c := range n.Consts {
+			Inspect(c, f)
+		}
+	case *Param:
+		Inspect(n.Name, f)
+	case *ProcDecl:
+		Inspect(n.Name, f)
+		for _, prm := range n.Params {
+			Inspect(prm, f)
+		}
+		inspectStmts(n.Body, f)
+	case *ClassDecl:
+		Inspect(n.Name, f)
+		inspectStmts(n.Members, f)
+	case *AssignStmt:
+		Inspect(n.Target, f)
+		Inspect(n.Value, f)
+	case *CallStmt:
+		Inspect(n.Callee, f)
+		inspectExprs(n.Args, f)
+	case *IfStmt:
+		Inspect(n.Cond, f)
+		inspectStmts(n.Then, f)
+		for _, c := range n.ElseIfs {
+			Inspect(c, f)
+		}
+		inspectStmts(n.Else, f)
+	case *ElseIfClause:
+		Inspect(n.Cond, f)
+		inspectStmts(n.Body, f)
+	case *SelectStmt:
+		Inspect(n.Value, f)
+		for _, c := range n.Cases {
+			Inspect(c, f)
+		}
+	case *CaseClause:
+		inspectExprs(n.Values, f)
+		inspectStmts(n.Body, f)
+	case *ForStmt:
+		Inspect(n.Var, f)
+		Inspect(n.From, f)
+		Inspect(n.To, f)
+		if n.Step != nil {
+			Inspect(n.Step, f)
+		}
+		inspectStmts(n.Body, f)
+	case *ForEachStmt:
+		Inspect(n.Var, f)
+		Inspect(n.In, f)
+		inspectStmts(n.Body, f)
+	case *DoStmt:
+		if n.Cond != nil {
+			Inspect(n.Cond, f)
+		}
+		inspectStmts(n.Body, f)
+	case *WhileStmt:
+		Inspect(n.Cond, f)
+		inspectStmts(n.Body, f)
+	case *WithStmt:
+		Inspect(n.Object, f)
+		inspectStmts(n.Body, f)
+	case *EraseStmt:
+		inspectExprs(n.Vars, f)
+	case *RandomizeStmt:
+		if n.Seed != nil {
+			Inspect(n.Seed, f)
+		}
+	case *ExecuteStmt:
+		Inspect(n.Code, f)
+	}
+}
+
+func inspectStmts(list []Stmt, f func(Node) bool) {
+	for _, s := range list {
+		Inspect(s, f)
+	}
+}
+
+func inspectExprs(list []Expr, f func(Node) bool) {
+	for _, e := range list {
+		Inspect(e, f)
+	}
+}
@@ -0,0 +1,311 @@
+package vbscanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is the character encoding of a source file.
+type Encoding int
+
+// Encodings
+const (
+	DetectEncoding Encoding = iota // detect the encoding from the source
+	UTF8                           // UTF-8 without a byte order mark
+	UTF8BOM                        // UTF-8 with a byte order mark
+	Windows1252                    // Windows-1252, the usual code page for western European text
+	UTF16LE                        // UTF-16 little endian without a byte order mark
+	UTF16BE                        // UTF-16 big endian without a byte order mark
+	UTF16LEBOM                     // UTF-16 little endian with a byte order mark
+	UTF16BEBOM                     // UTF-16 big endian with a byte order mark
+)
+
+var encodingNames = [...]string{
+	DetectEncoding: "detect",
+	UTF8:           "utf-8",
+	UTF8BOM:        "utf-8-bom",
+	Windows1252:    "windows-1252",
+	UTF16LE:        "utf-16le",
+	UTF16BE:        "utf-16be",
+	UTF16LEBOM:     "utf-16le-bom",
+	UTF16BEBOM:     "utf-16be-bom",
+}
+
+// String returns the name of the encoding, like "windows-1252".
+func (e Encoding) String() string {
+	if e < 0 || int(e) >= len(encodingNames) {
+		return "Encoding(" + strconv.Itoa(int(e)) + ")"
+	}
+	return encodingNames[e]
+}
+
+// ParseEncoding returns the encoding with the given name, as returned by
+// String. Code page numbers, like "1252" or "65001", are accepted too.
+func ParseEncoding(name string) (Encoding, error) {
+	for e, n := range encodingNames {
+		if strings.EqualFold(name, n) {
+			return Encoding(e), nil
+		}
+	}
+	if cp, err := strconv.Atoi(name); err == nil {
+		if e, ok := codePages[cp]; ok {
+			return e, nil
+		}
+	}
+	return DetectEncoding, fmt.Errorf("unknown encoding %q", name)
+}
+
+// codePages maps the code pages that may be given in a CodePage directive
+// to encodings.
+var codePages = map[int]Encoding{
+	65001: UTF8,
+	1252:  Windows1252,
+	28591: Windows1252, // ISO-8859-1, which Windows-1252 extends
+	1200:  UTF16LE,
+	1201:  UTF16BE,
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// codePageRE finds the CodePage attribute of a page directive.
+var codePageRE = regexp.MustCompile(`(?i)<%\s*@[^%]*\bcodepage\s*=\s*["']?(\d+)`)
+
+// NewDecoder returns a reader that converts the text read from r to UTF-8,
+// along with the encoding of r. If enc is DetectEncoding, the encoding is
+// found from a byte order mark, then from the CodePage of a page directive
+// near the start of the file, and otherwise by checking whether the text is
+// valid UTF-8, falling back to Windows-1252. A byte order mark is not passed on.
+func NewDecoder(r io.Reader, enc Encoding) (io.Reader, Encoding) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(br.Size())
+	if enc == DetectEncoding {
+		enc = detect(head)
+	}
+	switch enc {
+	case UTF8, UTF8BOM:
+		if bytes.HasPrefix(head, bomUTF8) {
+			br.Discard(len(bomUTF8))
+		}
+		return br, enc
+	case UTF16LE, UTF16LEBOM:
+		if bytes.HasPrefix(head, bomUTF16LE) {
+			br.Discard(len(bomUTF16LE))
+		}
+	case UTF16BE, UTF16BEBOM:
+		if bytes.HasPrefix(head, bomUTF16BE) {
+			br.Discard(len(bomUTF16BE))
+		}
+	}
+	return &decoder{r: br, enc: enc}, enc
+}
+
+// detect returns the encoding of text starting with head.
+func detect(head []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		return UTF8BOM
+	case bytes.HasPrefix(head, bomUTF16LE):
+		return UTF16LEBOM
+	case bytes.HasPrefix(head, bomUTF16BE):
+		return UTF16BEBOM
+	case len(head) >= 2 && head[0] != 0 && head[1] == 0:
+		return UTF16LE // ASCII text saved as UTF-16 without a byte order mark
+	case len(head) >= 2 && head[0] == 0 && head[1] != 0:
+		return UTF16BE
+	}
+	if m := codePageRE.FindSubmatch(head); m != nil {
+		cp, _ := strconv.Atoi(string(m[1]))
+		if e, ok := codePages[cp]; ok {
+			return e
+		}
+	}
+	// ignore a character cut off at the end of head
+	for i := 1; i < utf8.UTFMax && i <= len(head); i++ {
+		if utf8.RuneStart(head[len(head)-i]) {
+			if !utf8.FullRune(head[len(head)-i:]) {
+				head = head[:len(head)-i]
+			}
+			break
+		}
+	}
+	if utf8.Valid(head) {
+		return UTF8
+	}
+	return Windows1252
+}
+
+// windows1252 holds the characters for bytes 0x80 to 0x9F in Windows-1252.
+// The other bytes are the same as in ISO-8859-1. Unused bytes map to the
+// C1 control characters, as they do in Windows.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// decoder converts Windows-1252 or UTF-16 text to UTF-8.
+type decoder struct {
+	r       *bufio.Reader
+	enc     Encoding
+	pending []byte // decoded text not yet returned
+	err     error
+}
+
+// Read implements io.Reader.
+func (d *decoder) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		d.fill()
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+// fill decodes the next character into pending.
+func (d *decoder) fill() {
+	var r rune
+	switch d.enc {
+	case Windows1252:
+		b, err := d.r.ReadByte()
+		if err != nil {
+			d.err = err
+			return
+		}
+		r = rune(b)
+		if b >= 0x80 && b <= 0x9F {
+			r = windows1252[b-0x80]
+		}
+	default:
+		u, err := d.readUnit()
+		if err != nil {
+			d.err = err
+			return
+		}
+		r = rune(u)
+		if utf16.IsSurrogate(r) {
+			r = utf8.RuneError
+			if lo, err := d.peekUnit(); err == nil {
+				if dec := utf16.DecodeRune(rune(u), rune(lo)); dec != utf8.RuneError {
+					d.r.Discard(2)
+					r = dec
+				}
+			}
+		}
+	}
+	d.pending = utf8.AppendRune(d.pending[:0], r)
+}
+
+// readUnit reads a UTF-16 code unit.
+func (d *decoder) readUnit() (uint16, error) {
+	u, err := d.peekUnit()
+	if err == nil {
+		d.r.Discard(2)
+	} else if err == io.ErrUnexpectedEOF {
+		d.r.Discard(1)
+	}
+	return u, err
+}
+
+// peekUnit returns the next UTF-16 code unit without reading it.
+func (d *decoder) peekUnit() (uint16, error) {
+	b, err := d.r.Peek(2)
+	if len(b) < 2 {
+		if len(b) == 1 {
+			return utf8.RuneError, io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	if d.enc == UTF16BE || d.enc == UTF16BEBOM {
+		return uint16(b[0])<<8 | uint16(b[1]), nil
+	}
+	return uint16(b[1])<<8 | uint16(b[0]), nil
+}
+
+// NewEncoder returns a writer that converts the UTF-8 text written to it to
+// enc and writes it to w. For encodings with a byte order mark, the mark is
+// written first. Characters that cannot be represented in Windows-1252 are
+// written as '?'.
+func NewEncoder(w io.Writer, enc Encoding) io.Writer {
+	return &encoder{w: w, enc: enc}
+}
+
+// encoder converts UTF-8 text to another encoding.
+type encoder struct {
+	w       io.Writer
+	enc     Encoding
+	started bool   // whether the byte order mark has been written
+	partial []byte // start of a character split between writes
+}
+
+// Write implements io.Writer.
+func (e *encoder) Write(p []byte) (int, error) {
+	var out []byte
+	if !e.started {
+		e.started = true
+		switch e.enc {
+		case UTF8BOM:
+			out = append(out, bomUTF8...)
+		case UTF16LEBOM:
+			out = append(out, bomUTF16LE...)
+		case UTF16BEBOM:
+			out = append(out, bomUTF16BE...)
+		}
+	}
+	if e.enc == UTF8 || e.enc == UTF8BOM || e.enc == DetectEncoding {
+		out = append(out, p...)
+	} else {
+		text := append(e.partial, p...)
+		e.partial = nil
+		for len(text) > 0 {
+			if !utf8.FullRune(text) {
+				e.partial = append([]byte(nil), text...)
+				break
+			}
+			r, size := utf8.DecodeRune(text)
+			text = text[size:]
+			out = e.appendRune(out, r)
+		}
+	}
+	if _, err := e.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// appendRune appends r in the encoding of e.
+func (e *encoder) appendRune(out []byte, r rune) []byte {
+	switch e.enc {
+	case Windows1252:
+		if r < 0x80 || r >= 0xA0 && r < 0x100 {
+			return append(out, byte(r))
+		}
+		for i, c := range windows1252 {
+			if c == r {
+				return append(out, byte(0x80+i))
+			}
+		}
+		return append(out, '?')
+	default:
+		for _, u := range utf16.AppendRune(nil, r) {
+			if e.enc == UTF16BE || e.enc == UTF16BEBOM {
+				out = append(out, byte(u>>8), byte(u))
+			} else {
+				out = append(out, byte(u), byte(u>>8))
+			}
+		}
+		return out
+	}
+}
@@ -0,0 +1,91 @@
+package vbscanner
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestNewDecoder(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		enc  Encoding // given encoding
+		want Encoding // encoding detected or given
+		text string   // decoded text
+	}{
+		{"ascii", []byte("<% x = 1 %>"), DetectEncoding, UTF8, "<% x = 1 %>"},
+		{"utf-8", []byte("café"), DetectEncoding, UTF8, "café"},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFcafé"), DetectEncoding, UTF8BOM, "café"},
+		{"utf-16le bom", []byte("\xFF\xFEc\x00a\x00f\x00\xE9\x00"), DetectEncoding, UTF16LEBOM, "café"},
+		{"utf-16be bom", []byte("\xFE\xFF\x00c\x00a\x00f\x00\xE9"), DetectEncoding, UTF16BEBOM, "café"},
+		{"utf-16le no bom", []byte("<\x00%\x00"), DetectEncoding, UTF16LE, "<%"},
+		{"utf-16be no bom", []byte("\x00<\x00%"), DetectEncoding, UTF16BE, "<%"},
+		{"utf-16 surrogates", []byte("\xFF\xFE\x3D\xD8\x00\xDE"), DetectEncoding, UTF16LEBOM, "\U0001F600"},
+		{"windows-1252", []byte("caf\xE9 \x80"), DetectEncoding, Windows1252, "café €"},
+		{"codepage 1252", []byte("<%@ CodePage=1252 %>caf\xC3\xA9"), DetectEncoding, Windows1252, "<%@ CodePage=1252 %>cafÃ©"},
+		{"codepage 65001", []byte("<%@ Language=\"VBScript\" CODEPAGE=\"65001\" %>caf\xE9"), DetectEncoding, UTF8, "<%@ Language=\"VBScript\" CODEPAGE=\"65001\" %>caf\xE9"},
+		{"unknown codepage", []byte("<%@ CodePage=932 %>caf\xE9 au lait"), DetectEncoding, Windows1252, "<%@ CodePage=932 %>café au lait"},
+		{"given", []byte("caf\xC3\xA9"), Windows1252, Windows1252, "cafÃ©"},
+	}
+	for _, tt := range tests {
+		r, enc := NewDecoder(bytes.NewReader(tt.in), tt.enc)
+		if enc != tt.want {
+			t.Errorf("%s: encoding %v, want %v", tt.name, enc, tt.want)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if string(b) != tt.text {
+			t.Errorf("%s: text %q, want %q", tt.name, b, tt.text)
+		}
+	}
+}
+
+func TestEncoderRoundTrip(t *testing.T) {
+	const text = "<% s = \"café €\" %>\r\n"
+	for _, enc := range []Encoding{UTF8, UTF8BOM, Windows1252, UTF16LE, UTF16BE, UTF16LEBOM, UTF16BEBOM} {
+		var buf bytes.Buffer
+		w := NewEncoder(&buf, enc)
+		if _, err := io.WriteString(w, text); err != nil {
+			t.Errorf("%v: %v", enc, err)
+			continue
+		}
+		if _, got := NewDecoder(bytes.NewReader(buf.Bytes()), DetectEncoding); got != enc {
+			t.Errorf("%v: detected %v", enc, got)
+		}
+		r, _ := NewDecoder(bytes.NewReader(buf.Bytes()), enc)
+		b, _ := io.ReadAll(r)
+		if string(b) != text {
+			t.Errorf("%v: round trip gave %q, want %q", enc, b, text)
+		}
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		want Encoding
+		err  bool
+	}{
+		{"utf-8", UTF8, false},
+		{"UTF-8-BOM", UTF8BOM, false},
+		{"windows-1252", Windows1252, false},
+		{"1252", Windows1252, false},
+		{"65001", UTF8, false},
+		{"1200", UTF16LE, false},
+		{"utf-16le-bom", UTF16LEBOM, false},
+		{"932", DetectEncoding, true},
+		{"latin-9", DetectEncoding, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseEncoding(tt.name)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("ParseEncoding(%q) = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.err)
+		}
+	}
+	if s := Encoding(99).String(); s != "Encoding(99)" {
+		t.Errorf("Encoding(99).String() = %q", s)
+	}
+}
@@ -0,0 +1,36 @@
+package vbscanner
+
+//go:generate stringer -type=ErrorKind
+
+// ErrorKind describes the kind of problem found by the scanner
+type ErrorKind int
+
+// Error kinds
+const (
+	ReadError             ErrorKind = iota // the underlying reader failed
+	PrematureEOF                           // the stream ended in the middle of a token
+	UnterminatedString                     // a string literal is missing its closing quote
+	UnterminatedIdent                      // a bracketed identifier is missing its closing bracket
+	UnterminatedDate                       // a date literal is missing its closing #
+	InvalidDate                            // a date literal contains characters not allowed in dates
+	EmbeddedTerminator                     // a literal contains the ASP terminator %>
+	UnterminatedDirective                  // a page directive is missing its closing %>
+)
+
+// ScanError describes malformed input found by the scanner.
+type ScanError struct {
+	Kind ErrorKind // kind of error
+	Pos  Position  // where the error was found
+	Msg  string    // description of the error
+	Err  error     // underlying error, for ReadError
+}
+
+// Error implements the error interface.
+func (e *ScanError) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
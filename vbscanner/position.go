@@ -0,0 +1,41 @@
+package vbscanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position describes a location in the source being scanned.
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number, starting at 1 (character count per line)
+}
+
+// IsValid returns true if the position has been set.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in line:column form.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Advance returns the position after reading text starting at p. Lines may
+// end with CR LF, LF or a CR on its own.
+func (p Position) Advance(text string) Position {
+	for i, r := range text {
+		if r == '\n' || r == '\r' && !strings.HasPrefix(text[i+1:], "\n") {
+			p.Line++
+			p.Column = 1
+		} else {
+			p.Column++
+		}
+	}
+	p.Offset += len(text)
+	return p
+}
@@ -5,8 +5,10 @@ import (
 	"bufio"
 	"bytes"
 	"io"
+	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 //go:generate stringer -type=TokenType
@@ -16,17 +18,21 @@ type TokenType int
 
 // Token types
 const (
-	EOF     TokenType = iota // end of file
-	EOL                      // end of line - needed for vb
-	Ident                    // identifiers
-	String                   // string literals
-	Integer                  // integer literals
-	Float                    // float literals
-	Date                     // date literals
-	Comment                  // comments
-	Html                     // the HTML fragments in the ASP
-	Char                     // random characters like parens
-	Op                       // operators
+	EOF          TokenType = iota // end of file
+	EOL                           // end of line - needed for vb; the value is ":" or the line ending as written
+	Ident                         // identifiers
+	String                        // string literals
+	Integer                       // integer literals
+	Float                         // float literals
+	Date                          // date literals
+	Comment                       // comments
+	Html                          // the HTML fragments in the ASP
+	Char                          // random characters like parens
+	Op                            // operators
+	Output                        // the "=" opening an output block, like <%= expr %>
+	Directive                     // a page directive, like <%@ Language=VBScript %>
+	ScriptTag                     // the tags around a server script block, like <script runat="server">
+	Continuation                  // a line continuation, the "_" at the end of a line
 )
 
 //go:generate stringer -type=Mode
@@ -42,115 +48,339 @@ const (
 
 // Scanner reads a stream and provides VBS tokens scanned from it
 type Scanner struct {
-	rdr  *bufio.Reader
-	mode Mode
-	eof  bool
-	buf  bytes.Buffer
+	src    io.Reader // source given to Init, before decoding
+	rdr    *bufio.Reader
+	mode   Mode
+	eof    bool
+	buf    bytes.Buffer
+	pos    Position   // position of the next rune to be read
+	prev   Position   // position before the last rune read, for unread
+	err    *ScanError // first error found while scanning the current token
+	open   bool       // the last Html token ended with "<%"
+	script bool       // in a <script runat="server"> block rather than <% %>
+	tag    bool       // the last Html token ended at a <script runat="server"> tag
+
+	start Position     // start of the last token returned
+	end   Position     // end of the last token returned
+	ws    bytes.Buffer // whitespace skipped before the current token
+	lead  string       // whitespace skipped before the last token returned
+
+	lossless bool         // whether to keep the original text of every token
+	hta      bool         // whether the source is an HTML Application rather than an ASP page
+	dotted   bool         // whether identifiers include dots, like Server.CreateObject
+	encoding Encoding     // encoding of the source, or DetectEncoding
+	enc      Encoding     // encoding in use, once reading has started
+	raw      bytes.Buffer // source text read for the current token, in lossless mode
+	size     int          // size in bytes of the last rune read
+	pending  string       // source text read after the last token, in lossless mode
+	text     string       // original text of the last token returned
+	trail    string       // whitespace following the last token returned
 }
 
 // Init sets up the scanner with the given reader
 func (s *Scanner) Init(src io.Reader, initialMode Mode) {
-	s.rdr = bufio.NewReader(src)
+	s.src = src
+	s.rdr = nil
+	s.enc = DetectEncoding
 	s.mode = initialMode
+	s.eof = false
+	s.pos = Position{Offset: 0, Line: 1, Column: 1}
+	s.prev = s.pos
+	s.err = nil
+	s.open = false
+	s.script = false
+	s.tag = false
+	s.start = s.pos
+	s.end = s.pos
+	s.ws.Reset()
+	s.lead = ""
+	s.raw.Reset()
+	s.pending = ""
+	s.text = ""
+	s.trail = ""
+}
+
+// SetLossless turns lossless mode on or off. In lossless mode the scanner
+// keeps all of the source text, so that Leading, Text and Trailing of every
+// token, including the final EOF, concatenate to exactly the input.
+func (s *Scanner) SetLossless(on bool) {
+	s.lossless = on
+}
+
+// SetHTA turns HTA mode on or off. In HTA mode the scanner reads an HTML
+// Application, where VBScript runs in <script language="VBScript"> blocks
+// and <% %> is plain HTML.
+func (s *Scanner) SetHTA(on bool) {
+	s.hta = on
+}
+
+// SetDottedIdents turns dotted identifier mode on or off. By default a dot
+// between names is returned as a separate Char token, so Server.CreateObject
+// is scanned as Server, "." and CreateObject. In dotted identifier mode the
+// dots are kept within identifiers, as in earlier versions of the scanner.
+func (s *Scanner) SetDottedIdents(on bool) {
+	s.dotted = on
+}
+
+// SetEncoding sets the character encoding of the source, which is converted
+// to UTF-8 as it is read. By default the encoding is detected as described
+// for NewDecoder. It should be called before the first token is read.
+// Positions are offsets into the converted text.
+func (s *Scanner) SetEncoding(enc Encoding) {
+	s.encoding = enc
+}
+
+// Encoding returns the character encoding of the source, which is known
+// once the first token has been read.
+func (s *Scanner) Encoding() Encoding {
+	return s.enc
+}
+
+// SetPosition sets the position of the next rune read. It is used when the
+// source is a section of a larger file, and should be called after Init and
+// before the first token is read.
+func (s *Scanner) SetPosition(pos Position) {
+	s.pos = pos
+	s.prev = pos
+	s.start = pos
+	s.end = pos
+}
+
+// Leading returns the text that was skipped before the last token
+// returned by Scan or Next. Normally this is the whitespace before the token,
+// not including the end of line characters that form EOL tokens. In lossless
+// mode it is all of the source text since the previous token's trailing text,
+// including the <% and %> delimiters.
+func (s *Scanner) Leading() string {
+	return s.lead
+}
+
+// Text returns the original spelling of the last token returned by Scan or Next,
+// such as a string literal with its quotes. It is only set in lossless mode.
+func (s *Scanner) Text() string {
+	return s.text
+}
+
+// Trailing returns the spaces and tabs following the last token returned by
+// Scan or Next on the same line. It is only set in lossless mode.
+func (s *Scanner) Trailing() string {
+	return s.trail
+}
+
+// Span returns the start and end positions of the last token returned by
+// Scan or Next. The end position is just past the last character of the token.
+// For Html tokens the span covers the HTML only, not the surrounding <% and %>.
+func (s *Scanner) Span() (Position, Position) {
+	return s.start, s.end
+}
+
+// read returns the next rune and advances the position. It returns false
+// at the end of the stream or if the reader fails, in which case eof is set.
+func (s *Scanner) read() (rune, bool) {
+	var src [utf8.UTFMax]byte
+	// keep the bytes as read, since invalid UTF-8 decodes to RuneError,
+	// and to see whether a carriage return is followed by a line feed
+	b, _ := s.rdr.Peek(utf8.UTFMax)
+	copy(src[:], b)
+	r, size, err := s.rdr.ReadRune()
+	if err != nil {
+		if err != io.EOF && s.err == nil {
+			s.err = &ScanError{Kind: ReadError, Pos: s.pos, Msg: err.Error(), Err: err}
+		}
+		s.eof = true
+		return 0, false
+	}
+	if s.lossless {
+		s.raw.Write(src[:size])
+	}
+	s.size = size
+	s.prev = s.pos
+	s.pos.Offset += size
+	if r == '\n' || r == '\r' && src[1] != '\n' {
+		s.pos.Line++
+		s.pos.Column = 1
+	} else {
+		s.pos.Column++
+	}
+	return r, true
+}
+
+// unread puts back the last rune read.
+func (s *Scanner) unread() {
+	if s.rdr.UnreadRune() == nil {
+		s.pos = s.prev
+		if s.lossless {
+			s.raw.Truncate(s.raw.Len() - s.size)
+		}
+	}
+}
+
+// fail records an error for the current token. Only the first error is kept.
+func (s *Scanner) fail(kind ErrorKind, pos Position, msg string) {
+	if s.err == nil {
+		s.err = &ScanError{Kind: kind, Pos: pos, Msg: msg}
+	}
 }
 
 // nextIs reads the next rune and returns true if it matches c. If
 // it doesn't match, the read rune is unread.
 func (s *Scanner) nextIs(c rune) bool {
-	r, _, err := s.rdr.ReadRune()
-	if err == io.EOF {
+	r, ok := s.read()
+	if !ok {
 		return false
-	} else if err != nil {
-		panic(err)
 	} else if r == c {
 		return true
 	}
-	err = s.rdr.UnreadRune()
-	if err != nil {
-		panic(err)
-	}
+	s.unread()
 	return false
 }
 
-// nextIs reads the next rune and returns true if it matches c.
-// It always leaves the run unread.
+// peek returns true if the next rune matches c.
+// It always leaves the rune unread.
 func (s *Scanner) peek(c rune) bool {
-	x := false
-	r, _, err := s.rdr.ReadRune()
-	if err == io.EOF {
+	r, ok := s.read()
+	if !ok {
 		return false
-	} else if err != nil {
-		panic(err)
-	} else if r == c {
-		x = true
-	}
-	err = s.rdr.UnreadRune()
-	if err != nil {
-		panic(err)
 	}
-	return x
+	s.unread()
+	return r == c
 }
 
 // scanHtml returns the block of HTML up to the next "<%" or end of stream.
+// It sets the span of the token, since the "<%" is not part of it.
 func (s *Scanner) scanHtml() string {
 	s.buf.Reset()
+	s.start = s.pos
 	for {
-		r, _, err := s.rdr.ReadRune()
-		if err == io.EOF {
-			s.eof = true
+		if s.scriptBlock() {
+			s.mode = VBS_MODE
+			s.script = true
+			s.tag = true
+			s.end = s.pos
 			return s.buf.String()
-		} else if err != nil {
-			panic(err)
 		}
-
-		if r == '<' && s.nextIs('%') {
-			s.mode = VBS_MODE
+		r, ok := s.read()
+		if !ok {
+			s.end = s.pos
 			return s.buf.String()
 		}
+
+		if r == '<' {
+			lt := s.prev
+			if !s.hta && s.nextIs('%') {
+				s.mode = VBS_MODE
+				s.open = true
+				s.end = lt
+				return s.buf.String()
+			}
+		}
 		s.buf.WriteRune(r)
 	}
 }
 
-// temporary
-func (s *Scanner) scanCode() string {
-	s.buf.Reset()
-	for {
-		r, _, err := s.rdr.ReadRune()
-		if err == io.EOF {
-			s.eof = true
-			return s.buf.String()
-		} else if err != nil {
-			panic(err)
-		}
+// Scan returns the next token type and its value. It panics with a *ScanError
+// if the input is malformed; use Next to receive the error instead.
+func (s *Scanner) Scan() (TokenType, string) {
+	t, v, err := s.Next()
+	if err != nil {
+		panic(err)
+	}
+	return t, v
+}
 
-		if r == '%' && s.nextIs('>') {
-			s.mode = HTML_MODE
-			return s.buf.String()
+// Next returns the next token type and its value. If the input is malformed,
+// the token is returned along with a *ScanError describing the problem, and
+// the caller may continue scanning with the following call.
+func (s *Scanner) Next() (TokenType, string, error) {
+	if s.rdr == nil {
+		var r io.Reader
+		r, s.enc = NewDecoder(s.src, s.encoding)
+		s.rdr = bufio.NewReader(r)
+	}
+	s.ws.Reset()
+	s.raw.Reset()
+	base := s.pos.Offset
+	t, v := s.scan()
+	s.lead = s.ws.String()
+	if t != Html {
+		s.end = s.pos
+	}
+	if s.lossless {
+		s.splitRaw(t, base)
+	}
+	if s.err != nil {
+		err := s.err
+		s.err = nil
+		return t, v, err
+	}
+	return t, v, nil
+}
+
+// splitRaw divides the source text read for the last token into its leading
+// text, original spelling and trailing whitespace. base is the offset at which
+// reading for the token began.
+func (s *Scanner) splitRaw(t TokenType, base int) {
+	all := s.raw.String()
+	start, end := s.start.Offset-base, s.end.Offset-base
+	s.lead = s.pending + all[:start]
+	s.text = all[start:end]
+	s.pending = all[end:]
+	s.trail = ""
+	if t == Html || t == EOL || t == EOF || s.pending != "" {
+		return
+	}
+	s.raw.Reset()
+	for {
+		r, ok := s.read()
+		if !ok {
+			break
+		} else if r != ' ' && r != '\t' {
+			s.unread()
+			break
 		}
-		s.buf.WriteRune(r)
 	}
+	s.trail = s.raw.String()
 }
 
-// Scan returns the next token type and its value.
-func (s *Scanner) Scan() (TokenType, string) {
+// scan returns the next token type and its value, recording any problems in s.err.
+func (s *Scanner) scan() (TokenType, string) {
 	if s.eof {
+		s.start = s.pos
 		return EOF, ""
 	} else if s.mode == HTML_MODE {
 		return Html, s.scanHtml()
 	} else {
+		if s.tag {
+			s.tag = false
+			s.start = s.pos
+			r, _ := s.read()
+			return ScriptTag, s.scanTag(r)
+		}
+		if s.open {
+			s.open = false
+			s.start = s.pos
+			if s.nextIs('=') {
+				return Output, "="
+			} else if s.nextIs('@') {
+				return Directive, s.scanDirective()
+			}
+		}
 		for {
-			r, _, err := s.rdr.ReadRune()
-			if err == io.EOF {
-				s.eof = true
-				if s.buf.Len() != 0 {
-					panic("Premature EOF")
-				}
+			r, ok := s.read()
+			s.start = s.prev
+			if !ok {
+				s.start = s.pos
 				return EOF, ""
-			} else if err != nil {
-				panic(err)
 			}
 
-			if r == '%' && s.nextIs('>') {
+			if s.script {
+				if r == '<' && s.closesScript() {
+					tag := s.scanTag(r)
+					s.mode = HTML_MODE
+					s.script = false
+					return ScriptTag, tag
+				}
+			} else if r == '%' && s.nextIs('>') {
 				s.mode = HTML_MODE
 				return Html, s.scanHtml()
 			}
@@ -159,18 +389,10 @@ func (s *Scanner) Scan() (TokenType, string) {
 				if err == nil {
 					str := strings.ToLower(string(b[0:2]))
 					if str == "em" && (b[2] == '\t' || b[2] == ' ') {
-						b := make([]byte, 3)
-						_, err = s.rdr.Read(b)
-						if err != nil {
-							panic(err)
-						}
+						s.skip(3)
 						return Comment, s.scanComment()
 					} else if str == "em" && b[2] != '_' && !unicode.IsLetter(rune(b[2])) && !unicode.IsDigit(rune(b[2])) {
-						b := make([]byte, 2)
-						_, err = s.rdr.Read(b)
-						if err != nil {
-							panic(err)
-						}
+						s.skip(2)
 						return Comment, s.scanComment()
 					}
 				}
@@ -178,7 +400,7 @@ func (s *Scanner) Scan() (TokenType, string) {
 			if unicode.IsLetter(r) {
 				s := s.scanIdent(r)
 				switch strings.ToLower(s) {
-				case "mod", "and", "not", "or", "xor":
+				case "mod", "and", "not", "or", "xor", "eqv", "imp":
 					return Op, s
 				default:
 					return Ident, s
@@ -189,10 +411,18 @@ func (s *Scanner) Scan() (TokenType, string) {
 				return s.scanNumber(r)
 			} else if r == '&' && s.isHexOctNum() {
 				return s.scanNumber(r)
+			} else if r == '.' && s.isFraction() {
+				return s.scanNumber(r)
 			} else if unicode.IsSpace(r) {
 				if r == '\n' {
 					return EOL, "\n"
+				} else if r == '\r' {
+					if s.nextIs('\n') {
+						return EOL, "\r\n"
+					}
+					return EOL, "\r"
 				}
+				s.ws.WriteRune(r)
 			} else if r == ':' {
 				return EOL, ":"
 			} else if r == '"' {
@@ -213,6 +443,8 @@ func (s *Scanner) Scan() (TokenType, string) {
 				}
 			} else if r == '\\' && !s.nextIs('\\') {
 				return Op, string(r)
+			} else if r == '_' && s.atLineEnd() {
+				return Continuation, string(r)
 			} else {
 				return Char, string(r)
 			}
@@ -220,39 +452,74 @@ func (s *Scanner) Scan() (TokenType, string) {
 	}
 }
 
-// isHexOctNum returns true if the upcoming bytes (after the already read &) represent a number
+// skip reads and discards n runes.
+func (s *Scanner) skip(n int) {
+	for i := 0; i < n; i++ {
+		if _, ok := s.read(); !ok {
+			return
+		}
+	}
+}
+
+// atLineEnd returns true if only spaces and tabs remain before the end of
+// the line or the stream.
+func (s *Scanner) atLineEnd() bool {
+	for i := 1; ; i++ {
+		b, _ := s.rdr.Peek(i)
+		if len(b) < i {
+			return true
+		}
+		switch b[i-1] {
+		case ' ', '\t':
+		case '\r', '\n':
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// isHexOctNum returns true if the upcoming bytes (after the already read &)
+// represent a hexadecimal number, like &HFF, or an octal one, like &O17.
 func (s *Scanner) isHexOctNum() bool {
 	ch, err := s.rdr.Peek(2)
 	if err == nil {
-		if ch[0] == 'h' || ch[0] == 'H' || ch[0] == 'o' || ch[0] == 'O' {
-			if unicode.IsDigit(rune(ch[1])) {
-				return true
-			}
+		switch ch[0] {
+		case 'h', 'H':
+			return isHexDigit(rune(ch[1]))
+		case 'o', 'O':
+			return ch[1] >= '0' && ch[1] <= '7'
 		}
 	}
 	return false
 }
 
+// isFraction returns true if the upcoming byte (after the already read .)
+// is a digit, so the dot starts a number like .5.
+func (s *Scanner) isFraction() bool {
+	ch, err := s.rdr.Peek(1)
+	return err == nil && ch[0] >= '0' && ch[0] <= '9'
+}
+
+// isHexDigit returns true for the digits of hexadecimal numbers.
+func isHexDigit(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F'
+}
+
 // scanIdent scans an identifier
 func (s *Scanner) scanIdent(c rune) string {
 	s.buf.Reset()
 	s.buf.WriteRune(c)
 	for {
-		r, _, err := s.rdr.ReadRune()
-		if err == io.EOF {
-			s.eof = true
+		r, ok := s.read()
+		if !ok {
 			return s.buf.String()
-		} else if err != nil {
-			panic(err)
 		}
 
-		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' && s.dotted {
 			s.buf.WriteRune(r)
 		} else {
-			err = s.rdr.UnreadRune()
-			if err != nil {
-				panic(err)
-			}
+			s.unread()
 			return s.buf.String()
 		}
 	}
@@ -260,19 +527,20 @@ func (s *Scanner) scanIdent(c rune) string {
 
 // scanBracketIdent returns an identifier that was enclosed in brackets.
 func (s *Scanner) scanBracketIdent() string {
+	start := s.prev
 	s.buf.Reset()
 	for {
-		r, _, err := s.rdr.ReadRune()
-		if err == io.EOF {
-			s.eof = true
+		r, ok := s.read()
+		if !ok {
+			s.fail(PrematureEOF, s.pos, "Premature EOF")
 			return "[" + s.buf.String() + "]"
-		} else if err != nil {
-			panic(err)
 		}
 
 		if r != ']' {
 			if r == '\r' || r == '\n' {
-				panic("unterminated identifier literal")
+				s.unread()
+				s.fail(UnterminatedIdent, s.pos, "unterminated identifier literal")
+				return "[" + s.buf.String() + "]"
 			}
 			s.buf.WriteRune(r)
 		} else {
@@ -280,8 +548,8 @@ func (s *Scanner) scanBracketIdent() string {
 				s.buf.WriteRune(r)
 			} else {
 				str := s.buf.String()
-				if strings.Contains(str, "%>") {
-					panic("Identifier contains %>")
+				if s.containsTerminator(str) {
+					s.fail(EmbeddedTerminator, start, "Identifier contains "+s.terminator())
 				}
 				return "[" + str + "]"
 			}
@@ -291,19 +559,25 @@ func (s *Scanner) scanBracketIdent() string {
 
 // scanString returns a string
 func (s *Scanner) scanString() string {
+	start := s.prev
 	s.buf.Reset()
 	for {
-		r, _, err := s.rdr.ReadRune()
-		if err == io.EOF {
-			s.eof = true
+		if s.atTerminator() && !s.closedOnLine('"') {
+			// leave the end of the block to be scanned
+			s.fail(UnterminatedString, s.pos, "unterminated string literal")
+			return s.buf.String()
+		}
+		r, ok := s.read()
+		if !ok {
+			s.fail(PrematureEOF, s.pos, "Premature EOF")
 			return s.buf.String()
-		} else if err != nil {
-			panic(err)
 		}
 
 		if r != '"' {
 			if r == '\r' || r == '\n' {
-				panic("unterminated string literal")
+				s.unread()
+				s.fail(UnterminatedString, s.pos, "unterminated string literal")
+				return s.buf.String()
 			}
 			s.buf.WriteRune(r)
 		} else {
@@ -311,8 +585,8 @@ func (s *Scanner) scanString() string {
 				s.buf.WriteRune(r)
 			} else {
 				str := s.buf.String()
-				if strings.Contains(str, "%>") {
-					panic("String contains %>")
+				if s.containsTerminator(str) {
+					s.fail(EmbeddedTerminator, start, "String contains "+s.terminator())
 				}
 				return str
 			}
@@ -322,71 +596,80 @@ func (s *Scanner) scanString() string {
 
 // scanDate returns a date
 func (s *Scanner) scanDate() string {
+	start := s.prev
 	s.buf.Reset()
 	for {
-		r, _, err := s.rdr.ReadRune()
-		if err == io.EOF {
-			s.eof = true
+		if s.atTerminator() && !s.closedOnLine('#') {
+			// leave the end of the block to be scanned
+			s.fail(UnterminatedDate, s.pos, "unterminated Date literal")
+			return s.buf.String()
+		}
+		r, ok := s.read()
+		if !ok {
+			s.fail(PrematureEOF, s.pos, "Premature EOF")
 			return s.buf.String()
-		} else if err != nil {
-			panic(err)
 		}
 
 		if r != '#' {
-			const allowed = "/-: \tAPMJANFEBMARAPRMAYJUNJULAUGSEPOCTNOVDECapmjanfebmaraprmayjunjulaugsepoctnovdec"
+			// digits, separators, and letters for month names and AM or PM
+			const allowed = "/-:,. \t"
 			if r == '\r' || r == '\n' {
-				panic("unterminated Date literal")
-			} else if unicode.IsDigit(r) || strings.ContainsRune(allowed, r) {
+				s.unread()
+				s.fail(UnterminatedDate, s.pos, "unterminated Date literal")
+				return s.buf.String()
+			} else if r < utf8.RuneSelf && (unicode.IsDigit(r) || unicode.IsLetter(r) || strings.ContainsRune(allowed, r)) {
 				s.buf.WriteRune(r)
 			} else {
-				panic("Invalid date characters")
+				// keep going to the closing # so scanning can resume after the literal
+				s.fail(InvalidDate, s.prev, "Invalid date characters")
 			}
 		} else {
 			str := s.buf.String()
-			if strings.Contains(str, "%>") {
-				panic("Date contains %>")
+			if s.containsTerminator(str) {
+				s.fail(EmbeddedTerminator, start, "Date contains "+s.terminator())
 			}
 			return str
 		}
 	}
 }
 
-// scanInteger returns an integer or float
+// scanNumber returns an integer or float starting with the already read c,
+// which is a digit, a dot or the & of a hexadecimal or octal number.
+// Hexadecimal and octal numbers may end with the Long type character &,
+// like &HFF&. Floats may start or end with the dot, like .5 or 1.
 func (s *Scanner) scanNumber(c rune) (TokenType, string) {
 	s.buf.Reset()
 	s.buf.WriteRune(c)
 	var t = Integer
-	first := true
 	hex := false
 	oct := false
 	signReady := false
 	gotSign := false
 	gotE := false
-	gotDot := false
+	gotDot := c == '.'
+	if gotDot {
+		t = Float
+	}
+	if c == '&' {
+		r, _ := s.read()
+		s.buf.WriteRune(r)
+		hex = r == 'h' || r == 'H'
+		oct = !hex
+	}
 	for {
-		r, _, err := s.rdr.ReadRune()
-		if err == io.EOF {
-			s.eof = true
+		r, ok := s.read()
+		if !ok {
 			return t, s.buf.String()
-		} else if err != nil {
-			panic(err)
 		}
 
-		if first && r == 'h' || r == 'H' {
-			s.buf.WriteRune(r)
-			first = false
-			hex = true
-		} else if first && r == 'o' || r == 'O' {
-			s.buf.WriteRune(r)
-			first = false
-			oct = true
-		} else if unicode.IsDigit(r) {
+		if hex && isHexDigit(r) || !hex && unicode.IsDigit(r) {
 			s.buf.WriteRune(r)
 			signReady = false
-		} else if hex && (r == 'A' || r == 'B' || r == 'C' || r == 'D' || r == 'E' || r == 'F' || r == 'a' || r == 'b' || r == 'c' || r == 'd' || r == 'e' || r == 'f') {
+		} else if (hex || oct) && r == '&' {
+			// the Long type character ends the number
 			s.buf.WriteRune(r)
-			signReady = false
-		} else if !hex && !oct && !gotDot && r == '.' {
+			return t, s.buf.String()
+		} else if !hex && !oct && !gotDot && !gotE && r == '.' {
 			t = Float
 			s.buf.WriteRune(r)
 			signReady = false
@@ -401,45 +684,136 @@ func (s *Scanner) scanNumber(c rune) (TokenType, string) {
 			signReady = true
 			gotE = true
 		} else {
-			err = s.rdr.UnreadRune()
-			if err != nil {
-				panic(err)
-			}
+			s.unread()
 			return t, s.buf.String()
 		}
 	}
 }
 
+// scanDirective returns the text of a page directive following the "@",
+// up to but not including the closing "%>".
+func (s *Scanner) scanDirective() string {
+	s.buf.Reset()
+	for {
+		asp, err := s.rdr.Peek(2)
+		if err == nil && string(asp) == "%>" {
+			return s.buf.String()
+		}
+
+		r, ok := s.read()
+		if !ok {
+			s.fail(UnterminatedDirective, s.start, "unterminated page directive")
+			return s.buf.String()
+		}
+		s.buf.WriteRune(r)
+	}
+}
+
+// scriptBlock returns true if the upcoming bytes open a VBScript block to be
+// scanned as code. In ASP pages these are server-side blocks, like
+// <script language="VBScript" runat="server">, where VBScript is the default
+// language. In HTML Applications they are blocks marked as VBScript, which
+// run in the browser. Other script blocks are left as HTML.
+func (s *Scanner) scriptBlock() bool {
+	if b, err := s.rdr.Peek(7); err != nil || !strings.EqualFold(string(b), "<script") {
+		return false
+	}
+	b, _ := s.rdr.Peek(s.rdr.Size())
+	if len(b) < 8 || !strings.ContainsRune(" \t\r\n>", rune(b[7])) {
+		return false
+	}
+	end := bytes.IndexByte(b, '>')
+	if end < 0 {
+		return false
+	}
+	attrs := string(b[7:end])
+	m := scriptLanguage.FindStringSubmatch(attrs)
+	vbs := m != nil && (strings.EqualFold(m[1], "vbscript") || strings.EqualFold(m[1], "vbs")) || scriptType.MatchString(attrs)
+	if s.hta {
+		return vbs
+	}
+	return runatServer.MatchString(attrs) && (m == nil || vbs)
+}
+
+var (
+	runatServer    = regexp.MustCompile(`(?i)\brunat\s*=\s*["']?server\b`)
+	scriptLanguage = regexp.MustCompile(`(?i)\blanguage\s*=\s*["']?([\w.]+)`)
+	scriptType     = regexp.MustCompile(`(?i)\btype\s*=\s*["']?text/vbscript\b`)
+)
+
+// closesScript returns true if the upcoming bytes (after the already read <)
+// close a server script block.
+func (s *Scanner) closesScript() bool {
+	b, err := s.rdr.Peek(7)
+	return err == nil && strings.EqualFold(string(b), "/script")
+}
+
+// scanTag returns a script tag starting with the already read c, up to and
+// including the closing >.
+func (s *Scanner) scanTag(c rune) string {
+	s.buf.Reset()
+	s.buf.WriteRune(c)
+	for {
+		r, ok := s.read()
+		if !ok {
+			return s.buf.String()
+		}
+		s.buf.WriteRune(r)
+		if r == '>' {
+			return s.buf.String()
+		}
+	}
+}
+
+// terminator returns the text that ends the current block of code.
+func (s *Scanner) terminator() string {
+	if s.script {
+		return "</script"
+	}
+	return "%>"
+}
+
+// containsTerminator returns true if str contains the text that ends the
+// current block of code.
+func (s *Scanner) containsTerminator(str string) bool {
+	return strings.Contains(strings.ToLower(str), s.terminator())
+}
+
+// closedOnLine returns true if c appears in the rest of the line, so that it
+// may close the literal being scanned.
+func (s *Scanner) closedOnLine(c byte) bool {
+	b, _ := s.rdr.Peek(s.rdr.Size())
+	if i := bytes.IndexAny(b, "\r\n"); i >= 0 {
+		b = b[:i]
+	}
+	return bytes.IndexByte(b, c) >= 0
+}
+
+// atTerminator returns true if the upcoming bytes end the current block of code.
+func (s *Scanner) atTerminator() bool {
+	t := s.terminator()
+	b, err := s.rdr.Peek(len(t))
+	return err == nil && strings.EqualFold(string(b), t)
+}
+
 // scanComment returns the comment
 func (s *Scanner) scanComment() string {
 	s.buf.Reset()
 	for {
 		// check for ASP terminator in comment
-		asp, err := s.rdr.Peek(2)
-		if err == nil {
-			if string(asp) == "%>" {
-				return s.buf.String()
-			}
+		if s.atTerminator() {
+			return s.buf.String()
 		}
 
-		r, _, err := s.rdr.ReadRune()
-		if err == io.EOF {
-			s.eof = true
+		r, ok := s.read()
+		if !ok {
 			return s.buf.String()
-		} else if err != nil {
-			panic(err)
 		}
 
-		if r != '\n' {
-			if r != '\r' {
-				s.buf.WriteRune(r)
-			}
-		} else {
-			err = s.rdr.UnreadRune()
-			if err != nil {
-				panic(err)
-			}
+		if r == '\r' || r == '\n' {
+			s.unread()
 			return s.buf.String()
 		}
+		s.buf.WriteRune(r)
 	}
 }
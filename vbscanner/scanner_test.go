@@ -0,0 +1,200 @@
+package vbscanner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLossless(t *testing.T) {
+	src := []string{
+		`<html>`,
+		`<% Option Explicit %>`,
+		`<%`,
+		`  Dim s ' a comment`,
+		`  s = "He said ""hi"" " & _`,
+		`      #1/2/2003# : s = s & 1.5E3`,
+		`  If s <> "" Then Response.Write s`,
+		`  REM another comment`,
+		`%>`,
+		`<p><%= s %></p>`,
+		`<script language="VBScript" runat="server">`,
+		`Sub Foo(x)`,
+		`	x = &HFF& + [odd name]`,
+		`End Sub`,
+		`</script>`,
+		`</html>`,
+	}
+	tests := []struct {
+		name string
+		eol  string
+		end  string
+	}{
+		{"LF", "\n", "\n"},
+		{"CRLF", "\r\n", "\r\n"},
+		{"CR", "\r", "\r"},
+		{"mixed", "\r\n", "\n"},
+		{"no final EOL", "\n", ""},
+	}
+	for _, tt := range tests {
+		in := strings.Join(src, tt.eol) + tt.end
+		var s Scanner
+		s.Init(strings.NewReader(in), HTML_MODE)
+		s.SetLossless(true)
+		var out strings.Builder
+		for i := 0; ; i++ {
+			tok, _, err := s.Next()
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			out.WriteString(s.Leading())
+			out.WriteString(s.Text())
+			out.WriteString(s.Trailing())
+			if tok == EOF || i > 10000 {
+				break
+			}
+		}
+		if out.String() != in {
+			t.Errorf("%s: round trip gave %q, want %q", tt.name, out.String(), in)
+		}
+	}
+}
+
+// scanString returns the tokens scanned from src, like "Ident x", with the
+// kinds of the errors returned along with them, like "String abc UnterminatedString".
+func scanString(src string, mode Mode) []string {
+	var s Scanner
+	s.Init(strings.NewReader(src), mode)
+	var list []string
+	for {
+		tok, v, err := s.Next()
+		if tok == EOF {
+			return list
+		}
+		str := tok.String() + " " + v
+		if e, ok := err.(*ScanError); ok {
+			str += " " + e.Kind.String()
+		}
+		list = append(list, str)
+	}
+}
+
+func TestScanErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		kind ErrorKind
+		pos  Position
+		want string // tokens, continuing after the error
+	}{
+		{"x = \"abc\ny", UnterminatedString, Position{8, 1, 9}, "Ident x|Op =|String abc UnterminatedString|EOL \n|Ident y"},
+		{"x = [abc\ny", UnterminatedIdent, Position{8, 1, 9}, "Ident x|Op =|Ident [abc] UnterminatedIdent|EOL \n|Ident y"},
+		{"x = #1/2\ny", UnterminatedDate, Position{8, 1, 9}, "Ident x|Op =|Date 1/2 UnterminatedDate|EOL \n|Ident y"},
+		{"x = #1/;/2#\ny", InvalidDate, Position{7, 1, 8}, "Ident x|Op =|Date 1//2 InvalidDate|EOL \n|Ident y"},
+		{"x = #1/2", PrematureEOF, Position{8, 1, 9}, "Ident x|Op =|Date 1/2 PrematureEOF"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(scanString(tt.in, VBS_MODE), "|"); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.in, got, tt.want)
+		}
+		var s Scanner
+		s.Init(strings.NewReader(tt.in), VBS_MODE)
+		for {
+			tok, _, err := s.Next()
+			if err != nil {
+				if e, ok := err.(*ScanError); !ok || e.Kind != tt.kind || e.Pos != tt.pos {
+					t.Errorf("%q: got error %#v, want %v at %v", tt.in, err, tt.kind, tt.pos)
+				}
+				break
+			}
+			if tok == EOF {
+				t.Errorf("%q: no error", tt.in)
+				break
+			}
+		}
+	}
+
+	if got := strings.Join(scanString("<% s = \"a%>b\" %>", HTML_MODE), "|"); got != "Html |Ident s|Op =|String a%>b EmbeddedTerminator|Html " {
+		t.Errorf("terminator in string: got %q", got)
+	}
+	if got := strings.Join(scanString("<%@ Language=VBScript", HTML_MODE), "|"); got != "Html |Directive  Language=VBScript UnterminatedDirective" {
+		t.Errorf("unterminated directive: got %q", got)
+	}
+
+	defer func() {
+		if e, ok := recover().(*ScanError); !ok || e.Kind != PrematureEOF {
+			t.Errorf("Scan panicked with %#v, want a PrematureEOF *ScanError", e)
+		}
+	}()
+	var s Scanner
+	s.Init(strings.NewReader(`"abc`), VBS_MODE)
+	s.Scan()
+	t.Error("Scan did not panic")
+}
+
+func TestSpan(t *testing.T) {
+	var s Scanner
+	s.Init(strings.NewReader("<p>é</p><% Dim s\r\ns = \"é\" _\r\n+ 2 %>"), HTML_MODE)
+	tests := []struct {
+		tok        TokenType
+		start, end Position
+	}{
+		{Html, Position{0, 1, 1}, Position{9, 1, 9}},
+		{Ident, Position{12, 1, 12}, Position{15, 1, 15}},
+		{Ident, Position{16, 1, 16}, Position{17, 1, 17}},
+		{EOL, Position{17, 1, 17}, Position{19, 2, 1}},
+		{Ident, Position{19, 2, 1}, Position{20, 2, 2}},
+		{Op, Position{21, 2, 3}, Position{22, 2, 4}},
+		{String, Position{23, 2, 5}, Position{27, 2, 8}},
+		{Continuation, Position{28, 2, 9}, Position{29, 2, 10}},
+		{EOL, Position{29, 2, 10}, Position{31, 3, 1}},
+		{Op, Position{31, 3, 1}, Position{32, 3, 2}},
+		{Integer, Position{33, 3, 3}, Position{34, 3, 4}},
+		{Html, Position{37, 3, 7}, Position{37, 3, 7}},
+		{EOF, Position{37, 3, 7}, Position{37, 3, 7}},
+	}
+	for i, tt := range tests {
+		tok, _, _ := s.Next()
+		start, end := s.Span()
+		if tok != tt.tok || start != tt.start || end != tt.end {
+			t.Errorf("token %d: got %v at %+v-%+v, want %v at %+v-%+v", i, tok, start, end, tt.tok, tt.start, tt.end)
+		}
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	tests := []struct {
+		text string
+		want Position
+	}{
+		{"abc", Position{5, 2, 6}},
+		{"é", Position{4, 2, 4}},
+		{"a\nb", Position{5, 3, 2}},
+		{"a\r\nb", Position{6, 3, 2}},
+		{"a\rb", Position{5, 3, 2}},
+		{"\r", Position{3, 3, 1}},
+	}
+	start := Position{2, 2, 3}
+	for _, tt := range tests {
+		if got := start.Advance(tt.text); got != tt.want {
+			t.Errorf("Advance(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestScriptBlocks(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`<script language="VBScript" runat="server">Sub A</script>`, `Html |ScriptTag <script language="VBScript" runat="server">|Ident Sub|Ident A|ScriptTag </script>|Html `},
+		{`<SCRIPT RUNAT=Server>x</SCRIPT>`, `Html |ScriptTag <SCRIPT RUNAT=Server>|Ident x|ScriptTag </SCRIPT>|Html `},
+		{`<script runat='server' type="text/vbscript">x</script>`, `Html |ScriptTag <script runat='server' type="text/vbscript">|Ident x|ScriptTag </script>|Html `},
+		{`<script language="VBScript">x</script>`, `Html <script language="VBScript">x</script>`},
+		{`<script language="JScript" runat="server">x</script>`, `Html <script language="JScript" runat="server">x</script>`},
+		{`<script runat="server">If a < b Then x</script>`, `Html |ScriptTag <script runat="server">|Ident If|Ident a|Op <|Ident b|Ident Then|Ident x|ScriptTag </script>|Html `},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(scanString(tt.in, HTML_MODE), "|"); got != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.in, got, tt.want)
+		}
+	}
+}
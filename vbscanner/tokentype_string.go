@@ -19,11 +19,15 @@ func _() {
 	_ = x[Html-8]
 	_ = x[Char-9]
 	_ = x[Op-10]
+	_ = x[Output-11]
+	_ = x[Directive-12]
+	_ = x[ScriptTag-13]
+	_ = x[Continuation-14]
 }
 
-const _TokenType_name = "EOFEOLIdentStringIntegerFloatDateCommentHtmlCharOp"
+const _TokenType_name = "EOFEOLIdentStringIntegerFloatDateCommentHtmlCharOpOutputDirectiveScriptTagContinuation"
 
-var _TokenType_index = [...]uint8{0, 3, 6, 11, 17, 24, 29, 33, 40, 44, 48, 50}
+var _TokenType_index = [...]uint8{0, 3, 6, 11, 17, 24, 29, 33, 40, 44, 48, 50, 56, 65, 74, 86}
 
 func (i TokenType) String() string {
 	if i < 0 || i >= TokenType(len(_TokenType_index)-1) {
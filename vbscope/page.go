@@ -0,0 +1,84 @@
+package vbscope
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/ancientlore/vbscribble/vbinclude"
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbparser"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// ParsePage parses the page in fname and the files it includes, directly or
+// through other files, as located by r. The page comes first, followed by
+// the included files in the order they are first included; a file included
+// more than once is parsed once. The files are ready to be passed to
+// Resolve. Files with syntax errors are returned as far as they could be
+// parsed, and the errors, along with includes that could not be read, are
+// joined in err.
+func ParsePage(r *vbinclude.Resolver, fname string) (files []*vbparser.File, err error) {
+	var errs []error
+	seen := make(map[string]bool)
+	var parse func(name string, initFile bool) error
+	parse = func(name string, initFile bool) error {
+		fil, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer fil.Close()
+		var lex vblexer.Lex
+		if initFile {
+			lex.InitFile(fil, name)
+		} else {
+			// included files are always read as HTML
+			lex.Init(fil, name, vbscanner.HTML_MODE)
+		}
+		f, err := vbparser.Parse(&lex)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		files = append(files, f)
+		for _, inc := range Includes(f) {
+			p, err := r.Resolve(name, inc.Virtual, inc.Path)
+			if err == nil && !seen[key(p)] {
+				seen[key(p)] = true
+				err = parse(p, false)
+			}
+			if err != nil {
+				errs = append(errs, &vbinclude.Error{Filename: name, Pos: inc.Pos(), Path: inc.Path, Err: err})
+			}
+		}
+		return nil
+	}
+	seen[key(fname)] = true
+	if err := parse(fname, true); err != nil {
+		return nil, err
+	}
+	return files, errors.Join(errs...)
+}
+
+// Includes returns the #include directives of f, in order, including those
+// within blocks, as in <% If admin Then %><!--#include file="admin.asp"--><% End If %>.
+func Includes(f *vbparser.File) []*vbparser.IncludeStmt {
+	var list []*vbparser.IncludeStmt
+	vbparser.Inspect(f, func(n vbparser.Node) bool {
+		switch n := n.(type) {
+		case *vbparser.IncludeStmt:
+			list = append(list, n)
+		case vbparser.Expr:
+			return false
+		}
+		return true
+	})
+	return list
+}
+
+// key returns the name of a file for recognizing it when included again.
+func key(name string) string {
+	if abs, err := filepath.Abs(name); err == nil {
+		return abs
+	}
+	return filepath.Clean(name)
+}
@@ -0,0 +1,378 @@
+package vbscope
+
+import (
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbparser"
+)
+
+// builtins are the names VBScript provides that are not in the lexer's catalog.
+var builtins = []string{"Me", "RegExp", "WScript"}
+
+// universe returns a scope with the builtin functions, constants and
+// objects of the lexer's catalog.
+func universe() *Scope {
+	s := newScope(UniverseScope, nil, nil, nil)
+	for _, sym := range vblexer.Symbols() {
+		switch sym.Type {
+		case vblexer.STATEMENT, vblexer.KEYWORD, vblexer.KEYWORD_BOOL, vblexer.INTRINSIC_MEMBER:
+			continue
+		}
+		s.insert(&Symbol{Name: sym.Name, Kind: Builtin})
+	}
+	for _, name := range builtins {
+		s.insert(&Symbol{Name: name, Kind: Builtin})
+	}
+	return s
+}
+
+// Resolve builds the scopes of a program and resolves the names used in it.
+// The files share the page scope, as an ASP page shares it with the files it
+// includes. Like VBScript, names may be used before they are declared, and
+// a name used without a declaration is a variable of the procedure or page
+// in which it is first used. Names declared more than once, which VBScript
+// only allows for the procedures of a Property, keep every declaration.
+func Resolve(files ...*vbparser.File) *Info {
+	return ResolveObjects(nil, files...)
+}
+
+// ResolveObjects is like Resolve, for a program that may use the objects the
+// host creates for it by name, like those declared with <object> in a WSF
+// job or in global.asa. The objects are builtins of the program.
+func ResolveObjects(objects []string, files ...*vbparser.File) *Info {
+	u := universe()
+	for _, name := range objects {
+		if name != "" && u.Lookup(name) == nil {
+			u.insert(&Symbol{Name: name, Kind: Builtin})
+		}
+	}
+	info := &Info{
+		Universe: u,
+		Page:     newScope(PageScope, u, nil, nil),
+		Scopes:   make(map[vbparser.Node]*Scope),
+		Defs:     make(map[*vbparser.Ident]*Symbol),
+		Uses:     make(map[*vbparser.Ident]*Symbol),
+		With:     make(map[*vbparser.MemberExpr]*vbparser.WithStmt),
+	}
+	r := resolver{info: info}
+	for _, f := range files {
+		r.file, r.scope = f, info.Page
+		r.declare(f.Body)
+	}
+	for _, f := range files {
+		r.file, r.scope = f, info.Page
+		r.stmts(f.Body)
+	}
+	return info
+}
+
+// resolver holds the state of a resolution.
+type resolver struct {
+	info  *Info
+	file  *vbparser.File       // file being resolved
+	scope *Scope               // current scope
+	withs []*vbparser.WithStmt // enclosing With statements, innermost last
+	write bool                 // the names being resolved are assigned to
+}
+
+// bodies returns the statements nested in s, other than those of procedures
+// and classes, which have scopes of their own.
+func bodies(s vbparser.Stmt) [][]vbparser.Stmt {
+	switch s := s.(type) {
+	case *vbparser.IfStmt:
+		list := [][]vbparser.Stmt{s.Then}
+		for _, c := range s.ElseIfs {
+			list = append(list, c.Body)
+		}
+		return append(list, s.Else)
+	case *vbparser.SelectStmt:
+		var list [][]vbparser.Stmt
+		for _, c := range s.Cases {
+			list = append(list, c.Body)
+		}
+		return list
+	case *vbparser.ForStmt:
+		return [][]vbparser.Stmt{s.Body}
+	case *vbparser.ForEachStmt:
+		return [][]vbparser.Stmt{s.Body}
+	case *vbparser.DoStmt:
+		return [][]vbparser.Stmt{s.Body}
+	case *vbparser.WhileStmt:
+		return [][]vbparser.Stmt{s.Body}
+	case *vbparser.WithStmt:
+		return [][]vbparser.Stmt{s.Body}
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// Declarations
+
+// declare adds the names declared in list to the current scope, and creates
+// the scopes of the procedures and classes in it.
+func (r *resolver) declare(list []vbparser.Stmt) {
+	for _, s := range list {
+		switch s := s.(type) {
+		case *vbparser.DimStmt:
+			for _, v := range s.Vars {
+				r.define(v.Name, Variable, s.Access, v)
+			}
+		case *vbparser.ConstStmt:
+			for _, c := range s.Consts {
+				r.define(c.Name, Constant, s.Access, c)
+			}
+		case *vbparser.ProcDecl:
+			kind := Property
+			switch s.Kind {
+			case vbparser.SubProc:
+				kind = Sub
+			case vbparser.FunctionProc:
+				kind = Function
+			}
+			r.define(s.Name, kind, s.Access, s)
+			r.open(ProcScope, s)
+			for _, p := range s.Params {
+				r.define(p.Name, Parameter, vbparser.AccessNone, p)
+			}
+			r.declare(s.Body)
+			r.close()
+		case *vbparser.ClassDecl:
+			r.define(s.Name, Class, vbparser.AccessNone, s)
+			r.open(ClassScope, s)
+			r.declare(s.Members)
+			r.close()
+		default:
+			for _, body := range bodies(s) {
+				r.declare(body)
+			}
+		}
+	}
+}
+
+// define declares the symbol named by id in the current scope, or adds decl
+// to the symbol already declared with that name.
+func (r *resolver) define(id *vbparser.Ident, kind Kind, access vbparser.Access, decl vbparser.Node) *Symbol {
+	sym := r.scope.Lookup(id.Name)
+	if sym == nil {
+		sym = &Symbol{Name: id.Name, Kind: kind, Access: access, File: r.file}
+		r.scope.insert(sym)
+	}
+	sym.Decls = append(sym.Decls, decl)
+	r.info.Defs[id] = sym
+	return sym
+}
+
+// open makes the scope of node the current scope, creating it if needed.
+func (r *resolver) open(kind ScopeKind, node vbparser.Node) {
+	s, ok := r.info.Scopes[node]
+	if !ok {
+		s = newScope(kind, r.scope, node, r.file)
+		r.info.Scopes[node] = s
+	}
+	r.scope = s
+}
+
+// close returns to the scope enclosing the current one.
+func (r *resolver) close() {
+	r.scope = r.scope.Parent
+}
+
+// local returns the scope that receives the variables used without a
+// declaration: the current procedure, or the page.
+func (r *resolver) local() *Scope {
+	if r.scope.Kind == ProcScope {
+		return r.scope
+	}
+	return r.info.Page
+}
+
+// class returns the scope of the class being resolved, or nil.
+func (r *resolver) class() *Scope {
+	for s := r.scope; s != nil; s = s.Parent {
+		if s.Kind == ClassScope {
+			return s
+		}
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// Uses
+
+// stmts resolves the names used in list.
+func (r *resolver) stmts(list []vbparser.Stmt) {
+	for _, s := range list {
+		r.stmt(s)
+	}
+}
+
+// stmt resolves the names used in s.
+func (r *resolver) stmt(s vbparser.Stmt) {
+	switch s := s.(type) {
+	case *vbparser.OutputStmt:
+		r.expr(s.Value)
+	case *vbparser.DimStmt:
+		for _, v := range s.Vars {
+			r.exprs(v.Bounds)
+		}
+	case *vbparser.ReDimStmt:
+		for _, v := range s.Vars {
+			// ReDim declares the array if the name is not declared
+			if sym := r.scope.LookupParent(v.Name.Name); sym != nil && sym.Kind != Builtin {
+				r.write = true
+				r.ref(v.Name, sym)
+				r.write = false
+			} else {
+				scope := r.scope
+				r.scope = r.local()
+				r.define(v.Name, Variable, vbparser.AccessNone, v)
+				r.scope = scope
+			}
+			r.exprs(v.Bounds)
+		}
+	case *vbparser.ConstStmt:
+		for _, c := range s.Consts {
+			r.expr(c.Value)
+		}
+	case *vbparser.ProcDecl:
+		r.open(ProcScope, s)
+		r.stmts(s.Body)
+		r.close()
+	case *vbparser.ClassDecl:
+		r.open(ClassScope, s)
+		r.stmts(s.Members)
+		r.close()
+	case *vbparser.AssignStmt:
+		r.target(s.Target)
+		r.expr(s.Value)
+	case *vbparser.CallStmt:
+		r.expr(s.Callee)
+		r.exprs(s.Args)
+	case *vbparser.IfStmt:
+		r.expr(s.Cond)
+		r.stmts(s.Then)
+		for _, c := range s.ElseIfs {
+			r.expr(c.Cond)
+			r.stmts(c.Body)
+		}
+		r.stmts(s.Else)
+	case *vbparser.SelectStmt:
+		r.expr(s.Value)
+		for _, c := range s.Cases {
+			r.exprs(c.Values)
+			r.stmts(c.Body)
+		}
+	case *vbparser.ForStmt:
+		r.target(s.Var)
+		r.expr(s.From)
+		r.expr(s.To)
+		r.expr(s.Step)
+		r.stmts(s.Body)
+	case *vbparser.ForEachStmt:
+		r.target(s.Var)
+		r.expr(s.In)
+		r.stmts(s.Body)
+	case *vbparser.DoStmt:
+		r.expr(s.Cond)
+		r.stmts(s.Body)
+	case *vbparser.WhileStmt:
+		r.expr(s.Cond)
+		r.stmts(s.Body)
+	case *vbparser.WithStmt:
+		r.expr(s.Object)
+		r.withs = append(r.withs, s)
+		r.stmts(s.Body)
+		r.withs = r.withs[:len(r.withs)-1]
+	case *vbparser.EraseStmt:
+		r.exprs(s.Vars)
+	case *vbparser.RandomizeStmt:
+		r.expr(s.Seed)
+	case *vbparser.ExecuteStmt:
+		r.expr(s.Code)
+	}
+}
+
+// exprs resolves the names used in list.
+func (r *resolver) exprs(list []vbparser.Expr) {
+	for _, x := range list {
+		r.expr(x)
+	}
+}
+
+// expr resolves the names used in x, which may be nil.
+func (r *resolver) expr(x vbparser.Expr) {
+	switch x := x.(type) {
+	case *vbparser.Ident:
+		r.use(x)
+	case *vbparser.ParenExpr:
+		r.expr(x.X)
+	case *vbparser.UnaryExpr:
+		r.expr(x.X)
+	case *vbparser.BinaryExpr:
+		r.expr(x.X)
+		r.expr(x.Y)
+	case *vbparser.MemberExpr:
+		switch {
+		case x.X == nil:
+			if n := len(r.withs); n > 0 {
+				r.info.With[x] = r.withs[n-1]
+			}
+		case isMe(x.X):
+			r.expr(x.X)
+			if c := r.class(); c != nil {
+				if sym := c.Lookup(x.Name.Name); sym != nil {
+					r.ref(x.Name, sym)
+				}
+			}
+		default:
+			r.expr(x.X)
+		}
+	case *vbparser.CallOrIndexExpr:
+		r.expr(x.Fun)
+		r.exprs(x.Args)
+	case *vbparser.NewExpr:
+		if sym := r.scope.LookupParent(x.Class.Name); sym != nil {
+			r.ref(x.Class, sym)
+		} else {
+			r.info.New = append(r.info.New, Ref{Ident: x.Class, File: r.file})
+		}
+	}
+}
+
+// target resolves the names used in x, which is assigned to. A name
+// assigned directly, rather than an element or a member of it, is written.
+func (r *resolver) target(x vbparser.Expr) {
+	if id, ok := x.(*vbparser.Ident); ok {
+		r.write = true
+		r.use(id)
+		r.write = false
+		return
+	}
+	r.expr(x)
+}
+
+// isMe returns true if x is the Me keyword.
+func isMe(x vbparser.Expr) bool {
+	id, ok := x.(*vbparser.Ident)
+	return ok && strings.EqualFold(id.Name, "Me")
+}
+
+// use resolves the name id, declaring it as a variable if needed.
+func (r *resolver) use(id *vbparser.Ident) {
+	if id == nil {
+		return
+	}
+	sym := r.scope.LookupParent(id.Name)
+	if sym == nil {
+		sym = &Symbol{Name: id.Name, Kind: Variable, File: r.file, Implicit: true, Decls: []vbparser.Node{id}}
+		r.local().insert(sym)
+	}
+	r.ref(id, sym)
+}
+
+// ref records that id refers to sym.
+func (r *resolver) ref(id *vbparser.Ident, sym *Symbol) {
+	r.info.Uses[id] = sym
+	sym.Uses = append(sym.Uses, Ref{Ident: id, File: r.file, Write: r.write})
+}
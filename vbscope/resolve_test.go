@@ -0,0 +1,175 @@
+package vbscope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vbparser"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+const src = `Option Explicit
+Dim count
+Class Person
+	Private mName
+	Public Property Get Name()
+		Name = mName
+	End Property
+	Public Property Let Name(value)
+		mName = value
+		Me.Touch
+	End Property
+	Sub Touch()
+		count = count + 1
+	End Sub
+End Class
+Sub Main(p)
+	Dim local
+	With p
+		.Name = "x"
+		local = .Name
+	End With
+	total = count + local
+End Sub
+`
+
+// idents returns the identifiers named name in f, in order.
+func idents(f *vbparser.File, name string) []*vbparser.Ident {
+	var list []*vbparser.Ident
+	vbparser.Inspect(f, func(n vbparser.Node) bool {
+		if id, ok := n.(*vbparser.Ident); ok && strings.EqualFold(id.Name, name) {
+			list = append(list, id)
+		}
+		return true
+	})
+	return list
+}
+
+func TestResolve(t *testing.T) {
+	f, err := vbparser.ParseFile("test.vbs", strings.NewReader(src), vbscanner.VBS_MODE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := Resolve(f)
+
+	tests := []struct {
+		name   string
+		i      int    // which identifier with the name
+		kind   Kind   // kind of its symbol
+		scope  string // name of the procedure or class of its scope, or "" for the page
+		decls  int    // declarations of its symbol
+		uses   int    // uses of its symbol
+		writes int    // uses that assign to it
+	}{
+		{"count", 0, Variable, "", 1, 3, 1},       // Dim count
+		{"count", 2, Variable, "", 1, 3, 1},       // count + 1, in a class
+		{"count", 3, Variable, "", 1, 3, 1},       // count + local, in a procedure
+		{"Person", 0, Class, "", 1, 0, 0},         // Class Person
+		{"mName", 0, Variable, "Person", 1, 2, 1}, // Private mName
+		{"mName", 1, Variable, "Person", 1, 2, 1}, // Name = mName
+		{"mName", 2, Variable, "Person", 1, 2, 1}, // mName = value
+		{"Name", 0, Property, "Person", 2, 1, 1},  // Property Get Name
+		{"Name", 1, Property, "Person", 2, 1, 1},  // Name = mName sets the result
+		{"Name", 2, Property, "Person", 2, 1, 1},  // Property Let Name
+		{"value", 0, Parameter, "Name", 1, 1, 0},  // Property Let Name(value)
+		{"value", 1, Parameter, "Name", 1, 1, 0},  // mName = value
+		{"Me", 0, Builtin, "", 0, 1, 0},           // Me.Touch
+		{"Touch", 0, Sub, "Person", 1, 1, 0},      // Me.Touch
+		{"Touch", 1, Sub, "Person", 1, 1, 0},      // Sub Touch
+		{"Main", 0, Sub, "", 1, 0, 0},             // Sub Main
+		{"p", 0, Parameter, "Main", 1, 1, 0},      // Sub Main(p)
+		{"p", 1, Parameter, "Main", 1, 1, 0},      // With p
+		{"local", 0, Variable, "Main", 1, 2, 1},   // Dim local
+		{"local", 1, Variable, "Main", 1, 2, 1},   // local = .Name
+		{"local", 2, Variable, "Main", 1, 2, 1},   // count + local
+		{"total", 0, Variable, "Main", 1, 1, 1},   // used without a declaration
+	}
+	for _, tt := range tests {
+		ids := idents(f, tt.name)
+		if tt.i >= len(ids) {
+			t.Errorf("%s #%d: not found", tt.name, tt.i)
+			continue
+		}
+		sym := info.SymbolOf(ids[tt.i])
+		if sym == nil {
+			t.Errorf("%s #%d: not resolved", tt.name, tt.i)
+			continue
+		}
+		scope := ""
+		switch n := sym.Scope.Node.(type) {
+		case *vbparser.ProcDecl:
+			scope = n.Name.Name
+		case *vbparser.ClassDecl:
+			scope = n.Name.Name
+		}
+		if sym.Scope.Kind == UniverseScope {
+			scope = ""
+		}
+		writes := 0
+		for _, ref := range sym.Uses {
+			if ref.Write {
+				writes++
+			}
+		}
+		if sym.Kind != tt.kind || scope != tt.scope || len(sym.Decls) != tt.decls || len(sym.Uses) != tt.uses || writes != tt.writes {
+			t.Errorf("%s #%d: got %v in %q with %d decls, %d uses, %d writes; want %v in %q with %d decls, %d uses, %d writes",
+				tt.name, tt.i, sym.Kind, scope, len(sym.Decls), len(sym.Uses), writes,
+				tt.kind, tt.scope, tt.decls, tt.uses, tt.writes)
+		}
+		if tt.name == "total" && !sym.Implicit {
+			t.Errorf("total: not implicit")
+		}
+	}
+
+	// members of the With object refer to the With statement
+	var with *vbparser.WithStmt
+	vbparser.Inspect(f, func(n vbparser.Node) bool {
+		if w, ok := n.(*vbparser.WithStmt); ok {
+			with = w
+		}
+		return true
+	})
+	members := 0
+	vbparser.Inspect(f, func(n vbparser.Node) bool {
+		if m, ok := n.(*vbparser.MemberExpr); ok && m.X == nil {
+			members++
+			if info.With[m] != with {
+				t.Errorf(".%s at %v: With statement %v, want %v", m.Name.Name, m.Pos(), info.With[m], with)
+			}
+			if sym := info.SymbolOf(m.Name); sym != nil {
+				t.Errorf(".%s at %v: resolved to %s %s", m.Name.Name, m.Pos(), sym.Kind, sym.Name)
+			}
+		}
+		return true
+	})
+	if members != 2 {
+		t.Errorf("found %d members of the With object, want 2", members)
+	}
+}
+
+func TestResolveObjects(t *testing.T) {
+	f, err := vbparser.ParseFile("test.vbs", strings.NewReader("conn.Open\nCONN.Close\nother.Open\nx = Len(s)\n"), vbscanner.VBS_MODE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := ResolveObjects([]string{"Conn", "", "Len"}, f)
+	tests := []struct {
+		name     string
+		kind     Kind
+		implicit bool
+	}{
+		{"conn", Builtin, false},
+		{"other", Variable, true},
+		{"Len", Builtin, false},
+		{"s", Variable, true},
+	}
+	for _, tt := range tests {
+		sym := info.SymbolOf(idents(f, tt.name)[0])
+		if sym == nil || sym.Kind != tt.kind || sym.Implicit != tt.implicit {
+			t.Errorf("%s: got %+v, want %v, implicit %v", tt.name, sym, tt.kind, tt.implicit)
+		}
+	}
+	if sym := info.Universe.Lookup("conn"); sym == nil || len(sym.Uses) != 2 {
+		t.Errorf("conn: got %+v, want 2 uses", sym)
+	}
+}
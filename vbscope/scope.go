@@ -0,0 +1,127 @@
+// Package vbscope resolves the names used in VBScript programs to their
+// declarations.
+package vbscope
+
+import (
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vbparser"
+)
+
+//go:generate stringer -type=Kind
+//go:generate stringer -type=ScopeKind
+
+// Kind describes what a symbol is.
+type Kind int
+
+// Symbol kinds
+const (
+	Variable  Kind = iota // variable declared with Dim, Public, Private or ReDim, or used without a declaration
+	Constant              // constant declared with Const
+	Parameter             // parameter of a procedure
+	Sub                   // Sub procedure
+	Function              // Function procedure
+	Property              // Property, with its Get, Let and Set procedures
+	Class                 // class
+	Builtin               // builtin function, constant or object, like Len, vbCrLf or Response
+)
+
+// ScopeKind describes the part of a program a scope covers.
+type ScopeKind int
+
+// Scope kinds
+const (
+	UniverseScope ScopeKind = iota // the builtin names
+	PageScope                      // the page and the files it includes
+	ClassScope                     // the members of a class
+	ProcScope                      // the parameters and local names of a procedure
+)
+
+// Ref is an identifier in one of the files of a program.
+type Ref struct {
+	Ident *vbparser.Ident
+	File  *vbparser.File
+	Write bool // the identifier is assigned to, as in x = 1, For x = ... or ReDim x(...)
+}
+
+// Symbol is a named entity of a program, like a variable or a procedure.
+type Symbol struct {
+	Name     string          // name as first declared
+	Kind     Kind            // kind of symbol
+	Access   vbparser.Access // access given in the declaration
+	Scope    *Scope          // scope the symbol belongs to
+	File     *vbparser.File  // file of the first declaration, nil for builtins
+	Decls    []vbparser.Node // declarations, in order: *VarDecl, *ConstDecl, *Param, *ProcDecl or *ClassDecl, or the first *Ident used for implicit variables
+	Implicit bool            // a variable used without being declared
+	Uses     []Ref           // identifiers that refer to the symbol, in order
+}
+
+// Scope holds the symbols declared in part of a program. Names are not case
+// sensitive.
+type Scope struct {
+	Kind     ScopeKind
+	Parent   *Scope         // enclosing scope, nil for the universe
+	Node     vbparser.Node  // *ProcDecl or *ClassDecl, nil for the page and the universe
+	File     *vbparser.File // file of Node
+	Children []*Scope       // scopes of the classes and procedures declared in the scope
+	symbols  map[string]*Symbol
+	order    []*Symbol
+}
+
+// newScope returns an empty scope within parent.
+func newScope(kind ScopeKind, parent *Scope, node vbparser.Node, file *vbparser.File) *Scope {
+	s := &Scope{Kind: kind, Parent: parent, Node: node, File: file, symbols: make(map[string]*Symbol)}
+	if parent != nil {
+		parent.Children = append(parent.Children, s)
+	}
+	return s
+}
+
+// Lookup returns the symbol with the given name declared in the scope, or nil.
+func (s *Scope) Lookup(name string) *Symbol {
+	return s.symbols[strings.ToLower(name)]
+}
+
+// LookupParent returns the symbol that name refers to in the scope: the one
+// declared in the scope or, failing that, in the nearest enclosing scope.
+// It returns nil if there is none.
+func (s *Scope) LookupParent(name string) *Symbol {
+	for ; s != nil; s = s.Parent {
+		if sym := s.Lookup(name); sym != nil {
+			return sym
+		}
+	}
+	return nil
+}
+
+// Symbols returns the symbols declared in the scope, in order of declaration.
+func (s *Scope) Symbols() []*Symbol {
+	return s.order
+}
+
+// insert adds sym to the scope.
+func (s *Scope) insert(sym *Symbol) {
+	s.symbols[strings.ToLower(sym.Name)] = sym
+	s.order = append(s.order, sym)
+	sym.Scope = s
+}
+
+// Info holds the scopes of a program and the symbols its identifiers refer to.
+type Info struct {
+	Universe *Scope                                      // builtin names
+	Page     *Scope                                      // names declared at page level in any of the files
+	Scopes   map[vbparser.Node]*Scope                    // scopes of the *ProcDecl and *ClassDecl nodes
+	Defs     map[*vbparser.Ident]*Symbol                 // identifiers that declare symbols
+	Uses     map[*vbparser.Ident]*Symbol                 // identifiers that refer to symbols
+	With     map[*vbparser.MemberExpr]*vbparser.WithStmt // members of a With object, like .Name, and their With statement
+	New      []Ref                                       // class names after New that are not declared
+}
+
+// SymbolOf returns the symbol that id declares or refers to, or nil. Member
+// names are only resolved for members of Me.
+func (info *Info) SymbolOf(id *vbparser.Ident) *Symbol {
+	if sym := info.Defs[id]; sym != nil {
+		return sym
+	}
+	return info.Uses[id]
+}
@@ -0,0 +1,219 @@
+// Package vbwsf reads Windows Script Files (.wsf), the XML job files run by
+// Windows Script Host, and finds the VBScript code in them.
+//
+// The reader is as forgiving as Windows Script Host is for files without an
+// XML declaration: script code does not have to be escaped or wrapped in
+// CDATA, so that "If a < b" is allowed.
+package vbwsf
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+	"github.com/ancientlore/vbscribble/vbscanner"
+)
+
+// File is a parsed Windows Script File.
+type File struct {
+	Jobs []*Job
+}
+
+// Job is a <job> element. Elements outside of any job are added to a job
+// with no ID, positioned at the first of them.
+type Job struct {
+	ID         string
+	Pos        vbscanner.Position // position of the <job> tag
+	References []*Reference
+	Objects    []*Object
+	Scripts    []*Script
+}
+
+// Reference is a <reference> element, which makes the constants in a type
+// library available to the scripts in the job.
+type Reference struct {
+	Pos     vbscanner.Position
+	Object  string // ProgID of the type library, like ADODB.Recordset
+	GUID    string // GUID of the type library, if given instead of Object
+	Version string
+}
+
+// Object is an <object> element, which creates an object the scripts in the
+// job can use by its ID.
+type Object struct {
+	Pos     vbscanner.Position
+	ID      string
+	ProgID  string
+	ClassID string
+}
+
+// Script is a <script> element.
+type Script struct {
+	Pos      vbscanner.Position // position of the <script> tag
+	Language string             // language given in the tag, like VBScript
+	Src      string             // external script file, if any
+	Code     string             // code in the element, without any CDATA wrapper
+	Start    vbscanner.Position // position of the code in the file
+	End      vbscanner.Position // position just past the code
+}
+
+// IsVBScript returns true if the script is written in VBScript.
+func (sc *Script) IsVBScript() bool {
+	return strings.EqualFold(sc.Language, "VBScript") || strings.EqualFold(sc.Language, "VBS")
+}
+
+// Init prepares lex to read the code of the script, with positions relative
+// to the start of the file named fname.
+func (sc *Script) Init(lex *vblexer.Lex, fname string) {
+	lex.Init(strings.NewReader(sc.Code), fname, vbscanner.VBS_MODE)
+	lex.SetHTA(false)
+	lex.SetEncoding(vbscanner.UTF8)
+	lex.SetPosition(sc.Start)
+}
+
+// Error describes a problem with the structure of a file.
+type Error struct {
+	Pos vbscanner.Position
+	Msg string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+var (
+	tagRE   = regexp.MustCompile(`<!--[\s\S]*?-->|<(/?)([A-Za-z]+)\b([^>]*?)(/?)>`)
+	attrRE  = regexp.MustCompile(`([\w:-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+	closeRE = regexp.MustCompile(`(?i)</script\s*>`)
+)
+
+// Read reads a Windows Script File from r, converting it to UTF-8 if it
+// is in another encoding.
+func Read(r io.Reader) (*File, error) {
+	dec, _ := vbscanner.NewDecoder(r, vbscanner.DetectEncoding)
+	src, err := io.ReadAll(dec)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(src))
+}
+
+// Parse parses the text of a Windows Script File.
+func Parse(src string) (*File, error) {
+	f := &File{}
+	var p positions
+	var job *Job
+	current := func(pos vbscanner.Position) *Job {
+		if job == nil {
+			job = &Job{Pos: pos}
+			f.Jobs = append(f.Jobs, job)
+		}
+		return job
+	}
+	for off := 0; ; {
+		m := tagRE.FindStringSubmatchIndex(src[off:])
+		if m == nil {
+			return f, nil
+		}
+		for i := range m {
+			if m[i] >= 0 {
+				m[i] += off
+			}
+		}
+		off = m[1]
+		if m[2] < 0 {
+			continue // comment
+		}
+		closing, selfClosing := m[3] > m[2], m[9] > m[8]
+		name := strings.ToLower(src[m[4]:m[5]])
+		attrs := parseAttrs(src[m[6]:m[7]])
+		pos := p.at(src, m[0])
+		switch name {
+		case "job":
+			if closing {
+				job = nil
+			} else {
+				job = &Job{ID: attrs["id"], Pos: pos}
+				f.Jobs = append(f.Jobs, job)
+			}
+		case "reference":
+			if !closing {
+				j := current(pos)
+				j.References = append(j.References, &Reference{Pos: pos, Object: attrs["object"], GUID: attrs["guid"], Version: attrs["version"]})
+			}
+		case "object":
+			if !closing {
+				j := current(pos)
+				j.Objects = append(j.Objects, &Object{Pos: pos, ID: attrs["id"], ProgID: attrs["progid"], ClassID: attrs["classid"]})
+			}
+		case "script":
+			if closing {
+				continue
+			}
+			sc := &Script{Pos: pos, Language: attrs["language"], Src: attrs["src"]}
+			start, end := off, off
+			if !selfClosing {
+				c := closeRE.FindStringIndex(src[off:])
+				if c == nil {
+					return f, &Error{Pos: pos, Msg: "unterminated script element"}
+				}
+				end = off + c[0]
+				off += c[1]
+				start, end = unwrapCDATA(src, start, end)
+			}
+			sc.Code = src[start:end]
+			sc.Start = p.at(src, start)
+			sc.End = p.at(src, end)
+			j := current(pos)
+			j.Scripts = append(j.Scripts, sc)
+		}
+	}
+}
+
+// parseAttrs returns the attributes of a tag, with names in lower case.
+func parseAttrs(text string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range attrRE.FindAllStringSubmatch(text, -1) {
+		v := m[2]
+		if strings.HasPrefix(v, `"`) || strings.HasPrefix(v, "'") {
+			v = v[1 : len(v)-1]
+		}
+		attrs[strings.ToLower(m[1])] = v
+	}
+	return attrs
+}
+
+// unwrapCDATA returns the range of the code within src[start:end], which
+// excludes a CDATA wrapper if there is one.
+func unwrapCDATA(src string, start, end int) (int, int) {
+	const cdataOpen, cdataClose = "<![CDATA[", "]]>"
+	content := src[start:end]
+	trimmed := strings.TrimLeft(content, " \t\r\n")
+	if !strings.HasPrefix(trimmed, cdataOpen) {
+		return start, end
+	}
+	i := start + len(content) - len(trimmed) + len(cdataOpen)
+	if j := strings.LastIndex(src[i:end], cdataClose); j >= 0 {
+		return i, i + j
+	}
+	return i, end
+}
+
+// positions converts increasing byte offsets into positions.
+type positions struct {
+	pos vbscanner.Position
+	set bool
+}
+
+// at returns the position of the byte at offset in src. Offsets must not
+// decrease from one call to the next.
+func (p *positions) at(src string, offset int) vbscanner.Position {
+	if !p.set {
+		p.pos = vbscanner.Position{Line: 1, Column: 1}
+		p.set = true
+	}
+	p.pos = p.pos.Advance(src[p.pos.Offset:offset])
+	return p.pos
+}
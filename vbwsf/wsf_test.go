@@ -0,0 +1,109 @@
+package vbwsf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ancientlore/vbscribble/vblexer"
+)
+
+const wsf = `<package>
+<!-- <job id="commented"> -->
+<job id="first">
+	<reference object="ADODB.Recordset" version="2.8"/>
+	<object id="fso" progid="Scripting.FileSystemObject"/>
+	<script language="VBScript">
+		If a < b Then WScript.Echo "less"
+	</script>
+	<script language="JScript">var x = 1;</script>
+	<script language="VBScript" src="lib.vbs"/>
+</job>
+<job id="second">
+	<script language="VBS"><![CDATA[
+x = 1
+]]></script>
+</job>
+</package>
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse(wsf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(f.Jobs))
+	}
+	first, second := f.Jobs[0], f.Jobs[1]
+	if first.ID != "first" || first.Pos.Line != 3 || second.ID != "second" {
+		t.Errorf("jobs %q at %v and %q", first.ID, first.Pos, second.ID)
+	}
+	if len(first.References) != 1 || first.References[0].Object != "ADODB.Recordset" || first.References[0].Version != "2.8" {
+		t.Errorf("references %+v", first.References)
+	}
+	if len(first.Objects) != 1 || first.Objects[0].ID != "fso" || first.Objects[0].ProgID != "Scripting.FileSystemObject" {
+		t.Errorf("objects %+v", first.Objects)
+	}
+
+	if len(first.Scripts) != 3 || len(second.Scripts) != 1 {
+		t.Fatalf("got %d and %d scripts, want 3 and 1", len(first.Scripts), len(second.Scripts))
+	}
+	scripts := []struct {
+		sc   *Script
+		vbs  bool
+		src  string
+		code string
+		line int // line of the start of the code
+		col  int
+	}{
+		{first.Scripts[0], true, "", "\n\t\tIf a < b Then WScript.Echo \"less\"\n\t", 6, 30},
+		{first.Scripts[1], false, "", "var x = 1;", 9, 29},
+		{first.Scripts[2], true, "lib.vbs", "", 10, 45},
+		{second.Scripts[0], true, "", "\nx = 1\n", 13, 34},
+	}
+	for i, tt := range scripts {
+		sc := tt.sc
+		if sc.IsVBScript() != tt.vbs || sc.Src != tt.src || sc.Code != tt.code || sc.Start.Line != tt.line || sc.Start.Column != tt.col {
+			t.Errorf("script %d: got %v %q %q at %v, want %v %q %q at %d:%d",
+				i, sc.IsVBScript(), sc.Src, sc.Code, sc.Start, tt.vbs, tt.src, tt.code, tt.line, tt.col)
+		}
+	}
+}
+
+func TestScriptInit(t *testing.T) {
+	f, err := Parse(wsf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := f.Jobs[1].Scripts[0]
+	var lex vblexer.Lex
+	sc.Init(&lex, "test.wsf")
+	tok := lex.Next()
+	for tok.Type == vblexer.EOL {
+		tok = lex.Next()
+	}
+	if tok.Raw != "x" || tok.Start.Line != 14 || tok.Start.Column != 1 {
+		t.Errorf("first token %q at %v, want x at 14:1", tok.Raw, tok.Start)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		jobs int
+		err  bool
+	}{
+		{`<script language="VBScript">x = 1`, 0, true},
+		{`<object id="o" progid="A.B"/><script language="VBScript">x = 1</script>`, 1, false},
+		{``, 0, false},
+	}
+	for _, tt := range tests {
+		f, err := Parse(tt.in)
+		if (err != nil) != tt.err || f == nil || len(f.Jobs) != tt.jobs {
+			t.Errorf("%q: got %v with error %v, want %d jobs and error %v", tt.in, f, err, tt.jobs, tt.err)
+		}
+	}
+	if _, err := Read(strings.NewReader("\xFF\xFE<\x00j\x00o\x00b\x00>\x00")); err != nil {
+		t.Errorf("Read UTF-16: %v", err)
+	}
+}